@@ -0,0 +1,488 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// AISMessage is implemented by all the decoded AIS messages, e.g.
+// *AISPositionReport.
+type AISMessage interface {
+	// Header returns the fields that are common to all the messages.
+	Header() AISHeader
+}
+
+// AISHeader holds the fields that are common to all the AIS messages and is
+// embedded in all the decoded messages.
+type AISHeader struct {
+	MessageType     byte   // e.g. 1 for a position report.
+	RepeatIndicator byte   // number of times the message was repeated. 0 to 3.
+	MMSI            uint32 // Maritime Mobile Service Identity of the station that sent the message.
+}
+
+func (h AISHeader) Header() AISHeader {
+	return h
+}
+
+// AISDimensions are the distances, in meters, from the reference point of
+// the reported position to each side of the vessel or aid to navigation. 0
+// when not available.
+type AISDimensions struct {
+	ToBow       uint16
+	ToStern     uint16
+	ToPort      byte
+	ToStarboard byte
+}
+
+// AISPositionReport is the position report of a class A station, that is,
+// the message types 1, 2 and 3.
+//
+// NB The optional fields are not Valid when they are "not available" in the
+// message.
+type AISPositionReport struct {
+	AISHeader
+	NavigationStatus  byte              // 0=Under way using engine; 1=At anchor; 2=Not under command; 3=Restricted manoeuvrability; 4=Constrained by her draught; 5=Moored; 6=Aground; 7=Engaged in fishing; 8=Under way sailing; ... 15=Not defined.
+	RateOfTurn        int8              // raw ROTAIS value. see TurnRate.
+	SpeedOverGround   Optional[float32] // in knots. 102.2 means 102.2 knots or more.
+	PositionAccuracy  bool              // true=high (<= 10 m); false=low (> 10 m).
+	Longitude         Optional[float64]
+	Latitude          Optional[float64]
+	CourseOverGround  Optional[float32] // in degrees. true.
+	TrueHeading       Optional[uint16]  // in degrees.
+	Timestamp         byte              // UTC second of the report. 60=not available; 61=manual input mode; 62=estimated mode; 63=inoperative.
+	ManeuverIndicator byte              // 0=not available; 1=no special maneuver; 2=special maneuver.
+	RAIM              bool              // whether Receiver Autonomous Integrity Monitoring is in use.
+	RadioStatus       uint32
+}
+
+// TurnRate returns the rate of turn in degrees per minute, negative when
+// turning to port. It's not Valid when the rate of turn is not available or
+// when there is no turn indicator, that is, when RateOfTurn is -128, -127 or
+// 127.
+func (m *AISPositionReport) TurnRate() Optional[float32] {
+	if m.RateOfTurn == -128 || m.RateOfTurn == -127 || m.RateOfTurn == 127 {
+		return Optional[float32]{}
+	}
+
+	rate := float64(m.RateOfTurn) / 4.733
+
+	return Some(float32(math.Copysign(rate*rate, rate)))
+}
+
+// AISStaticVoyageData is the static and voyage related data of a class A
+// station, that is, the message type 5.
+type AISStaticVoyageData struct {
+	AISHeader
+	AISVersion      byte   // 0=ITU-R M.1371-1; 1=ITU-R M.1371-3; 2=ITU-R M.1371-5.
+	IMONumber       uint32 // 0 when not available.
+	CallSign        string
+	ShipName        string
+	ShipType        byte // 0=not available; e.g. 30=Fishing; 60=Passenger; 70=Cargo; 80=Tanker.
+	Dimensions      AISDimensions
+	EPFD            byte              // type of electronic position fixing device. 0=Undefined; 1=GPS; 2=GLONASS; 3=Combined GPS/GLONASS; ...
+	ETAMonth        byte              // 1 to 12. 0=not available.
+	ETADay          byte              // 1 to 31. 0=not available.
+	ETAHour         byte              // 0 to 23. 24=not available.
+	ETAMinute       byte              // 0 to 59. 60=not available.
+	Draught         Optional[float32] // in meters.
+	Destination     string
+	DTENotAvailable bool // whether the data terminal equipment is not ready.
+}
+
+// AISClassBPositionReport is the position report of a class B station, that
+// is, the message type 18.
+//
+// NB The optional fields are not Valid when they are "not available" in the
+// message.
+type AISClassBPositionReport struct {
+	AISHeader
+	SpeedOverGround  Optional[float32] // in knots. 102.2 means 102.2 knots or more.
+	PositionAccuracy bool              // true=high (<= 10 m); false=low (> 10 m).
+	Longitude        Optional[float64]
+	Latitude         Optional[float64]
+	CourseOverGround Optional[float32] // in degrees. true.
+	TrueHeading      Optional[uint16]  // in degrees.
+	Timestamp        byte              // UTC second of the report. 60=not available; 61=manual input mode; 62=estimated mode; 63=inoperative.
+	CSUnit           bool              // true=carrier sense (CS) unit; false=SOTDMA unit.
+	Display          bool              // whether the unit has a display for the message types 12 and 14.
+	DSC              bool              // whether the unit has a Digital Selective Calling function.
+	Band             bool              // whether the unit can use the whole marine band.
+	Message22        bool              // whether the frequencies can be managed with the message type 22.
+	Assigned         bool              // whether the unit is in assigned mode.
+	RAIM             bool              // whether Receiver Autonomous Integrity Monitoring is in use.
+	RadioStatus      uint32
+}
+
+// AISExtendedClassBPositionReport is the extended position report of a
+// class B station, that is, the message type 19.
+//
+// NB The optional fields are not Valid when they are "not available" in the
+// message.
+type AISExtendedClassBPositionReport struct {
+	AISHeader
+	SpeedOverGround  Optional[float32] // in knots. 102.2 means 102.2 knots or more.
+	PositionAccuracy bool              // true=high (<= 10 m); false=low (> 10 m).
+	Longitude        Optional[float64]
+	Latitude         Optional[float64]
+	CourseOverGround Optional[float32] // in degrees. true.
+	TrueHeading      Optional[uint16]  // in degrees.
+	Timestamp        byte              // UTC second of the report. 60=not available; 61=manual input mode; 62=estimated mode; 63=inoperative.
+	ShipName         string
+	ShipType         byte // 0=not available; e.g. 30=Fishing; 36=Sailing; 37=Pleasure craft.
+	Dimensions       AISDimensions
+	EPFD             byte // type of electronic position fixing device. 0=Undefined; 1=GPS; 2=GLONASS; 3=Combined GPS/GLONASS; ...
+	RAIM             bool // whether Receiver Autonomous Integrity Monitoring is in use.
+	DTENotAvailable  bool // whether the data terminal equipment is not ready.
+	Assigned         bool // whether the unit is in assigned mode.
+}
+
+// AISAidToNavigationReport is the report of an aid to navigation (e.g. a
+// buoy or a lighthouse), that is, the message type 21.
+//
+// NB The optional fields are not Valid when they are "not available" in the
+// message.
+type AISAidToNavigationReport struct {
+	AISHeader
+	AidType          byte   // 0=not specified; 1=Reference point; 3=Fixed structure off shore; ...
+	Name             string // including the name extension.
+	PositionAccuracy bool   // true=high (<= 10 m); false=low (> 10 m).
+	Longitude        Optional[float64]
+	Latitude         Optional[float64]
+	Dimensions       AISDimensions
+	EPFD             byte // type of electronic position fixing device. 0=Undefined; 1=GPS; 2=GLONASS; 3=Combined GPS/GLONASS; ... 7=Surveyed.
+	Timestamp        byte // UTC second of the report. 60=not available; 61=manual input mode; 62=estimated mode; 63=inoperative.
+	OffPosition      bool // whether a floating aid is off its assigned position.
+	RAIM             bool // whether Receiver Autonomous Integrity Monitoring is in use.
+	VirtualAid       bool // whether the aid does not physically exist.
+	Assigned         bool // whether the station is in assigned mode.
+}
+
+// AISStaticDataReport is the static data report of a class B station, that
+// is, the message type 24. It is sent in two parts, each as a message of its
+// own: part A (PartNumber 0) has the ShipName and part B (PartNumber 1) has
+// the other fields.
+type AISStaticDataReport struct {
+	AISHeader
+	PartNumber     byte // 0=part A; 1=part B.
+	ShipName       string
+	ShipType       byte // 0=not available; e.g. 30=Fishing; 36=Sailing; 37=Pleasure craft.
+	VendorID       string
+	UnitModel      byte
+	SerialNumber   uint32
+	CallSign       string
+	Dimensions     AISDimensions // only when the station is not an auxiliary craft.
+	MothershipMMSI uint32        // only when the station is an auxiliary craft, i.e. its MMSI is 98XXXYYYY.
+}
+
+// the bits of a de-armored AIS payload.
+type aisPayload struct {
+	data   []byte // six bits in each byte.
+	length int    // in bits.
+}
+
+// de-armors the payload, that is, turns each character into its six bits.
+// the fill bits are the number of bits that were added to the end of the
+// payload to complete its last character.
+func newAISPayload(payload string, fillBits byte) (*aisPayload, error) {
+	data := make([]byte, len(payload))
+
+	for i := 0; i < len(payload); i++ {
+		value, ok := dearmorAIS(payload[i])
+		if !ok {
+			return nil, fmt.Errorf("Failed to de-armor character %q", payload[i])
+		}
+		data[i] = value
+	}
+
+	length := len(data)*6 - int(fillBits)
+	if length < 0 {
+		return nil, fmt.Errorf("Failed to de-armor payload with %d fill bits", fillBits)
+	}
+
+	return &aisPayload{data: data, length: length}, nil
+}
+
+// returns the six bits of an armored payload character. the valid
+// characters are 0 to W and ` to w.
+func dearmorAIS(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= 'W':
+		return c - '0', true
+	case c >= '`' && c <= 'w':
+		return c - '`' + 40, true
+	}
+	return 0, false
+}
+
+// returns the unsigned integer of length bits that starts at the start bit.
+// the bits after the end of the payload are zero, as some stations send
+// messages that are a few bits shorter than specified.
+func (p *aisPayload) uint(start, length int) uint32 {
+	result := uint32(0)
+
+	for i := start; i < start+length; i++ {
+		result <<= 1
+		if i < p.length && p.data[i/6]&(0x20>>(i%6)) != 0 {
+			result |= 1
+		}
+	}
+
+	return result
+}
+
+// returns the two's complement signed integer of length bits that starts at
+// the start bit.
+func (p *aisPayload) int(start, length int) int32 {
+	return int32(p.uint(start, length)<<(32-length)) >> (32 - length)
+}
+
+func (p *aisPayload) bool(start int) bool {
+	return p.uint(start, 1) != 0
+}
+
+// returns the six-bit ASCII text of length bits that starts at the start
+// bit, without the trailing @ padding and spaces.
+func (p *aisPayload) text(start, length int) string {
+	var b strings.Builder
+
+	for i := start; i+6 <= start+length; i += 6 {
+		c := byte(p.uint(i, 6))
+		if c < 32 {
+			c += 64
+		}
+		b.WriteByte(c)
+	}
+
+	return strings.TrimRight(b.String(), "@ ")
+}
+
+// returns the longitude in 1/10000 minutes that starts at the start bit.
+// 181 degrees means not available.
+func (p *aisPayload) longitude(start int) Optional[float64] {
+	longitude := float64(p.int(start, 28)) / 600000
+	if longitude < -180 || longitude > 180 {
+		return Optional[float64]{}
+	}
+	return Some(longitude)
+}
+
+// returns the latitude in 1/10000 minutes that starts at the start bit. 91
+// degrees means not available.
+func (p *aisPayload) latitude(start int) Optional[float64] {
+	latitude := float64(p.int(start, 27)) / 600000
+	if latitude < -90 || latitude > 90 {
+		return Optional[float64]{}
+	}
+	return Some(latitude)
+}
+
+// returns the speed over ground in 1/10 knots that starts at the start bit.
+// 1023 means not available.
+func (p *aisPayload) speed(start int) Optional[float32] {
+	speed := p.uint(start, 10)
+	if speed == 1023 {
+		return Optional[float32]{}
+	}
+	return Some(float32(speed) / 10)
+}
+
+// returns the course over ground in 1/10 degrees that starts at the start
+// bit. 3600 means not available.
+func (p *aisPayload) course(start int) Optional[float32] {
+	course := p.uint(start, 12)
+	if course >= 3600 {
+		return Optional[float32]{}
+	}
+	return Some(float32(course) / 10)
+}
+
+// returns the true heading in degrees that starts at the start bit. 511
+// means not available.
+func (p *aisPayload) heading(start int) Optional[uint16] {
+	heading := p.uint(start, 9)
+	if heading >= 360 {
+		return Optional[uint16]{}
+	}
+	return Some(uint16(heading))
+}
+
+// returns the dimensions that start at the start bit.
+func (p *aisPayload) dimensions(start int) AISDimensions {
+	return AISDimensions{
+		ToBow:       uint16(p.uint(start, 9)),
+		ToStern:     uint16(p.uint(start+9, 9)),
+		ToPort:      byte(p.uint(start+18, 6)),
+		ToStarboard: byte(p.uint(start+24, 6)),
+	}
+}
+
+// the minimum number of bits of each supported message type.
+var aisMessageLengths = map[byte]int{
+	1:  168,
+	2:  168,
+	3:  168,
+	5:  420,
+	18: 168,
+	19: 312,
+	21: 272,
+	24: 160,
+}
+
+// decodes the payload of an AIS message. returns a nil message when the
+// message type is not supported.
+func decodeAIS(payload string, fillBits byte) (AISMessage, error) {
+	p, err := newAISPayload(payload, fillBits)
+	if err != nil {
+		return nil, err
+	}
+
+	header := AISHeader{
+		MessageType:     byte(p.uint(0, 6)),
+		RepeatIndicator: byte(p.uint(6, 2)),
+		MMSI:            p.uint(8, 30),
+	}
+
+	length, ok := aisMessageLengths[header.MessageType]
+	if !ok {
+		return nil, nil
+	}
+
+	if p.length < length {
+		return nil, fmt.Errorf("Failed to decode message type %d: expected at least %d bits but got %d", header.MessageType, length, p.length)
+	}
+
+	switch header.MessageType {
+	case 1, 2, 3:
+		return &AISPositionReport{
+			AISHeader:         header,
+			NavigationStatus:  byte(p.uint(38, 4)),
+			RateOfTurn:        int8(p.int(42, 8)),
+			SpeedOverGround:   p.speed(50),
+			PositionAccuracy:  p.bool(60),
+			Longitude:         p.longitude(61),
+			Latitude:          p.latitude(89),
+			CourseOverGround:  p.course(116),
+			TrueHeading:       p.heading(128),
+			Timestamp:         byte(p.uint(137, 6)),
+			ManeuverIndicator: byte(p.uint(143, 2)),
+			RAIM:              p.bool(148),
+			RadioStatus:       p.uint(149, 19),
+		}, nil
+
+	case 5:
+		result := &AISStaticVoyageData{
+			AISHeader:       header,
+			AISVersion:      byte(p.uint(38, 2)),
+			IMONumber:       p.uint(40, 30),
+			CallSign:        p.text(70, 42),
+			ShipName:        p.text(112, 120),
+			ShipType:        byte(p.uint(232, 8)),
+			Dimensions:      p.dimensions(240),
+			EPFD:            byte(p.uint(270, 4)),
+			ETAMonth:        byte(p.uint(274, 4)),
+			ETADay:          byte(p.uint(278, 5)),
+			ETAHour:         byte(p.uint(283, 5)),
+			ETAMinute:       byte(p.uint(288, 6)),
+			Destination:     p.text(302, 120),
+			DTENotAvailable: p.bool(422),
+		}
+		if draught := p.uint(294, 8); draught != 0 {
+			result.Draught = Some(float32(draught) / 10)
+		}
+		return result, nil
+
+	case 18:
+		return &AISClassBPositionReport{
+			AISHeader:        header,
+			SpeedOverGround:  p.speed(46),
+			PositionAccuracy: p.bool(56),
+			Longitude:        p.longitude(57),
+			Latitude:         p.latitude(85),
+			CourseOverGround: p.course(112),
+			TrueHeading:      p.heading(124),
+			Timestamp:        byte(p.uint(133, 6)),
+			CSUnit:           p.bool(141),
+			Display:          p.bool(142),
+			DSC:              p.bool(143),
+			Band:             p.bool(144),
+			Message22:        p.bool(145),
+			Assigned:         p.bool(146),
+			RAIM:             p.bool(147),
+			RadioStatus:      p.uint(148, 20),
+		}, nil
+
+	case 19:
+		return &AISExtendedClassBPositionReport{
+			AISHeader:        header,
+			SpeedOverGround:  p.speed(46),
+			PositionAccuracy: p.bool(56),
+			Longitude:        p.longitude(57),
+			Latitude:         p.latitude(85),
+			CourseOverGround: p.course(112),
+			TrueHeading:      p.heading(124),
+			Timestamp:        byte(p.uint(133, 6)),
+			ShipName:         p.text(143, 120),
+			ShipType:         byte(p.uint(263, 8)),
+			Dimensions:       p.dimensions(271),
+			EPFD:             byte(p.uint(301, 4)),
+			RAIM:             p.bool(305),
+			DTENotAvailable:  p.bool(306),
+			Assigned:         p.bool(307),
+		}, nil
+
+	case 21:
+		return &AISAidToNavigationReport{
+			AISHeader:        header,
+			AidType:          byte(p.uint(38, 5)),
+			Name:             p.text(43, 120) + p.text(272, p.length-272),
+			PositionAccuracy: p.bool(163),
+			Longitude:        p.longitude(164),
+			Latitude:         p.latitude(192),
+			Dimensions:       p.dimensions(219),
+			EPFD:             byte(p.uint(249, 4)),
+			Timestamp:        byte(p.uint(253, 6)),
+			OffPosition:      p.bool(259),
+			RAIM:             p.bool(268),
+			VirtualAid:       p.bool(269),
+			Assigned:         p.bool(270),
+		}, nil
+
+	case 24:
+		result := &AISStaticDataReport{
+			AISHeader:  header,
+			PartNumber: byte(p.uint(38, 2)),
+		}
+
+		switch result.PartNumber {
+		case 0:
+			result.ShipName = p.text(40, 120)
+
+		case 1:
+			if p.length < 162 {
+				return nil, fmt.Errorf("Failed to decode message type %d part B: expected at least %d bits but got %d", header.MessageType, 162, p.length)
+			}
+
+			result.ShipType = byte(p.uint(40, 8))
+			result.VendorID = p.text(48, 18)
+			result.UnitModel = byte(p.uint(66, 4))
+			result.SerialNumber = p.uint(70, 20)
+			result.CallSign = p.text(90, 42)
+
+			if header.MMSI/10000000 == 98 {
+				result.MothershipMMSI = p.uint(132, 30)
+			} else {
+				result.Dimensions = p.dimensions(132)
+			}
+
+		default:
+			return nil, fmt.Errorf("Failed to decode message type %d: invalid part number %d", header.MessageType, result.PartNumber)
+		}
+
+		return result, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,237 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type vdmVisitor struct {
+	visitor
+	results []*VDM
+	errs    []error
+}
+
+func (v *vdmVisitor) OnAfterParse(sentenceType, sentence string, err error) {
+	if err != nil {
+		v.errs = append(v.errs, err)
+	}
+}
+
+func (v *vdmVisitor) OnVDM(vdm *VDM) {
+	v.results = append(v.results, vdm)
+}
+
+var (
+	vdm1 = "!AIVDM,2,1,1,A,55?MbV02;H;s<HtKR20EHE:0@T4@Dn2222222216L961O5Gf0NSQEp6ClRp8,0*1C"
+	vdm2 = "!AIVDM,2,2,1,A,88888888880,2*25"
+)
+
+var vdmStaticVoyageData = &VDM{
+	BaseSentence: BaseSentence{Talker: TalkerAIS, Text: vdm1 + "\r\n" + vdm2},
+	MessageID:    Some[byte](1),
+	Channel:      'A',
+	Payload:      "55?MbV02;H;s<HtKR20EHE:0@T4@Dn2222222216L961O5Gf0NSQEp6ClRp888888888880",
+	FillBits:     2,
+	Message: &AISStaticVoyageData{
+		AISHeader:   AISHeader{MessageType: 5, MMSI: 351759000},
+		IMONumber:   9134270,
+		CallSign:    "3FOF8",
+		ShipName:    "EVER DIADEM",
+		ShipType:    70,
+		Dimensions:  AISDimensions{ToBow: 225, ToStern: 70, ToPort: 1, ToStarboard: 31},
+		EPFD:        1,
+		ETAMonth:    5,
+		ETADay:      15,
+		ETAHour:     14,
+		Draught:     Some[float32](12.2),
+		Destination: "NEW YORK"}}
+
+func TestVDMAssembly(t *testing.T) {
+	// a fragment of another message, i.e. with another sequential message ID.
+	other := "!AIVDM,2,1,2,A,55?MbV02;H;s<HtKR20EHE:0@T4@Dn2222222216L961O5Gf0NSQEp6ClRp8,0*"
+	other += checksum(other)
+
+	// a fragment of the same message received on the other channel.
+	otherChannel := "!AIVDM,2,1,1,B,55?MbV02;H;s<HtKR20EHE:0@T4@Dn2222222216L961O5Gf0NSQEp6ClRp8,0*"
+	otherChannel += checksum(otherChannel)
+
+	tests := []struct {
+		name      string
+		sentences []string
+		expected  []*VDM
+	}{
+		{"in order", []string{vdm1, vdm2}, []*VDM{vdmStaticVoyageData}},
+		{"out of order", []string{vdm2, vdm1}, []*VDM{vdmStaticVoyageData}},
+		{"interleaved", []string{vdm1, other, otherChannel, vdm2}, []*VDM{vdmStaticVoyageData}},
+		{"incomplete", []string{vdm1}, nil},
+		{"repeated", []string{vdm1, vdm1, vdm2, vdm2}, []*VDM{vdmStaticVoyageData}},
+	}
+
+	for _, test := range tests {
+		visitor := &vdmVisitor{}
+
+		err := Visit(strings.NewReader(strings.Join(test.sentences, "\r\n")), visitor)
+		if err != nil {
+			t.Errorf("%s: unexpected error `%v`", test.name, err)
+		}
+
+		if !reflect.DeepEqual(test.expected, visitor.results) {
+			t.Errorf("%s: expected `%v` but got `%v`", test.name, test.expected, visitor.results)
+		}
+
+		if len(visitor.errs) != 0 {
+			t.Errorf("%s: unexpected errors `%v`", test.name, visitor.errs)
+		}
+	}
+}
+
+func TestVDMAssemblyDecodeError(t *testing.T) {
+	// a position report that is too short.
+	sentence := "!AIVDM,1,1,,A,15RTgt0PAso;90TKcjM8h6g,0*"
+	sentence += checksum(sentence)
+
+	visitor := &vdmVisitor{}
+
+	Visit(strings.NewReader(sentence), visitor)
+
+	if len(visitor.results) != 0 {
+		t.Errorf("expected no results but got `%v`", visitor.results)
+	}
+
+	var fieldError *FieldError
+	if len(visitor.errs) != 1 || !errors.As(visitor.errs[0], &fieldError) {
+		t.Fatalf("expected a FieldError but got `%v`", visitor.errs)
+	}
+
+	if fieldError.SentenceType != "AIVDM" || fieldError.Index != 4 || fieldError.Name != "Payload" {
+		t.Errorf("unexpected FieldError `%v`", fieldError)
+	}
+}
+
+func TestAISTurnRate(t *testing.T) {
+	tests := []struct {
+		rateOfTurn int8
+		expected   Optional[float32]
+	}{
+		{0, Some[float32](0)},
+		{10, Some[float32](4.464029)},
+		{-10, Some[float32](-4.464029)},
+		{126, Some[float32](708.7092)},
+		{127, Optional[float32]{}},
+		{-127, Optional[float32]{}},
+		{-128, Optional[float32]{}},
+	}
+
+	for _, test := range tests {
+		m := &AISPositionReport{RateOfTurn: test.rateOfTurn}
+
+		if actual := m.TurnRate(); actual != test.expected {
+			t.Errorf("%d expected to be `%v` but it's actually `%v`", test.rateOfTurn, test.expected, actual)
+		}
+	}
+}
+
+func TestMarshalVDM(t *testing.T) {
+	actual, err := Marshal(vdmStaticVoyageData)
+	if err != nil {
+		t.Fatalf("unexpected error `%v`", err)
+	}
+
+	// the fill bits are only in the last fragment.
+	expected := vdm1 + "\r\n" + vdm2
+
+	if actual != expected {
+		t.Errorf("expected `%s` but it's actually `%s`", expected, actual)
+	}
+
+	// without a talker ID.
+	actual, err = Marshal(&VDM{OwnVessel: true, Payload: "B52K>;h00Fc>jpUlNV@ikwpUoP06"})
+	if err != nil {
+		t.Fatalf("unexpected error `%v`", err)
+	}
+
+	if expected := "!AIVDO,1,1,,,B52K>;h00Fc>jpUlNV@ikwpUoP06,0*" + checksum("!AIVDO,1,1,,,B52K>;h00Fc>jpUlNV@ikwpUoP06,0*"); actual != expected {
+		t.Errorf("expected `%s` but it's actually `%s`", expected, actual)
+	}
+
+	// more than 9 fragments.
+	_, err = Marshal(&VDM{Payload: strings.Repeat("0", 9*maxVDMPayloadLength+1)})
+	if err == nil {
+		t.Error("expected an error for a payload that needs more than 9 fragments")
+	}
+}
+
+func TestMarshalVDMRoundTrip(t *testing.T) {
+	// without a message ID, so one is assigned to join the fragments.
+	vdm := *vdmStaticVoyageData
+	vdm.BaseSentence = BaseSentence{}
+	vdm.MessageID = Optional[byte]{}
+
+	sentences, err := Marshal(&vdm)
+	if err != nil {
+		t.Fatalf("unexpected error `%v`", err)
+	}
+
+	var results []Sentence
+
+	for s, err := range Sentences(strings.NewReader(sentences)) {
+		if err != nil {
+			t.Fatalf("unexpected error `%v`", err)
+		}
+		results = append(results, s)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("`%s` expected a single message but got %d", sentences, len(results))
+	}
+
+	actual := results[0].(*VDM)
+
+	if strings.Count(actual.Text, "\r\n") != 1 {
+		t.Errorf("`%s` expected 2 fragments", actual.Text)
+	}
+
+	if !actual.MessageID.Valid {
+		t.Errorf("`%s` expected a message ID", actual.Text)
+	}
+
+	if actual.Payload != vdm.Payload || actual.FillBits != vdm.FillBits {
+		t.Errorf("expected the payload `%s` but it's actually `%s`", vdm.Payload, actual.Payload)
+	}
+
+	if !reflect.DeepEqual(vdm.Message, actual.Message) {
+		t.Errorf("expected `%v` but it's actually `%v`", vdm.Message, actual.Message)
+	}
+}
+
+func TestMarshalVDMMessageID(t *testing.T) {
+	vdm := &VDM{Channel: 'A', Payload: strings.Repeat("0", maxVDMPayloadLength+1)}
+
+	// returns the message ID of the fragments marshaled by the encoder.
+	messageID := func(e *Encoder) string {
+		sentences, err := e.Marshal(vdm)
+		if err != nil {
+			t.Fatalf("unexpected error `%v`", err)
+		}
+		return strings.Split(sentences, ",")[3]
+	}
+
+	e := &Encoder{}
+
+	if actual := messageID(e); actual != "1" {
+		t.Errorf("expected the message ID 1 but it's actually %s", actual)
+	}
+
+	if actual := messageID(e); actual != "2" {
+		t.Errorf("expected the message ID 2 but it's actually %s", actual)
+	}
+
+	// each Encoder has its own sequential message IDs.
+	if actual := messageID(&Encoder{}); actual != "1" {
+		t.Errorf("expected the message ID 1 from another Encoder but it's actually %s", actual)
+	}
+}
@@ -0,0 +1,250 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// BackpressurePolicy defines what happens when a subscriber channel is full.
+type BackpressurePolicy int
+
+const (
+	DropOldest BackpressurePolicy = iota // discard the oldest buffered sentence to make room for the new one.
+	DropNewest                           // discard the new sentence.
+	Block                                // wait until the subscriber receives a sentence.
+)
+
+type SubscribeOptions struct {
+	Types      []string           // sentence types to receive. e.g. GGA for all the talkers or GNGGA for a single one. empty receives all the types.
+	BufferSize int                // size of the channel buffer. 0 uses 16.
+	Policy     BackpressurePolicy // what happens when the channel buffer is full.
+}
+
+// Subscription receives the sentences of a Broadcaster.
+type Subscription struct {
+	C <-chan Sentence // closed when the Broadcaster stops or the subscription is cancelled.
+
+	c           chan Sentence
+	types       map[string]bool
+	policy      BackpressurePolicy
+	broadcaster *Broadcaster
+	done        chan struct{}
+	doneOnce    sync.Once
+	dropped     atomic.Uint64
+	mutex       sync.Mutex // guards closed and the sends to c.
+	closed      bool
+}
+
+// Broadcaster parses the sentences read from a single reader (e.g. a serial
+// port) and sends them to all its subscribers.
+//
+// The sentences are parsed like in Visit, e.g. the "message N of M" GPGSV
+// sentences are joined into a single *GPGSV. The sentences that cannot be
+// parsed are not sent to the subscribers.
+//
+// The sentences are sent to one subscriber at a time, so a subscriber with
+// the Block policy delays all the other subscribers until it receives.
+type Broadcaster struct {
+	reader        io.Reader
+	options       []ParseOption
+	mutex         sync.Mutex
+	subscriptions map[*Subscription]bool
+	stopped       bool
+}
+
+func NewBroadcaster(reader io.Reader, options ...ParseOption) *Broadcaster {
+	return &Broadcaster{
+		reader:        reader,
+		options:       options,
+		subscriptions: make(map[*Subscription]bool),
+	}
+}
+
+// Subscribe returns a new subscription. When the Broadcaster was already
+// stopped, the subscription channel is already closed.
+func (b *Broadcaster) Subscribe(options SubscribeOptions) *Subscription {
+	bufferSize := options.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = 16
+	}
+
+	c := make(chan Sentence, bufferSize)
+
+	s := &Subscription{
+		C:           c,
+		c:           c,
+		policy:      options.Policy,
+		broadcaster: b,
+		done:        make(chan struct{}),
+	}
+
+	if len(options.Types) > 0 {
+		s.types = make(map[string]bool, len(options.Types))
+		for _, t := range options.Types {
+			s.types[t] = true
+		}
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.stopped {
+		s.close()
+	} else {
+		b.subscriptions[s] = true
+	}
+
+	return s
+}
+
+// Unsubscribe cancels the subscription and closes its channel.
+func (s *Subscription) Unsubscribe() {
+	// unblock the broadcaster when it's blocked sending to this subscription.
+	s.doneOnce.Do(func() {
+		close(s.done)
+	})
+
+	b := s.broadcaster
+
+	b.mutex.Lock()
+	delete(b.subscriptions, s)
+	b.mutex.Unlock()
+
+	s.close()
+}
+
+// closes the channel once. waits for a send in progress.
+func (s *Subscription) close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.c)
+	}
+}
+
+// Dropped returns the number of sentences that were discarded due to the
+// DropOldest or DropNewest policy.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Run reads and broadcasts the sentences until the reader reaches EOF, is
+// closed, or ctx is done (a blocked read is interrupted like in VisitContext).
+// Then it closes all the subscription channels.
+//
+// Run must only be called once. Returns the reader error like VisitContext.
+func (b *Broadcaster) Run(ctx context.Context) error {
+	defer b.stop()
+
+	return readSentences(ctx, b.reader, broadcastHandler{ctx, b}, b.options)
+}
+
+func (b *Broadcaster) stop() {
+	b.mutex.Lock()
+
+	b.stopped = true
+
+	subscriptions := make([]*Subscription, 0, len(b.subscriptions))
+	for s := range b.subscriptions {
+		delete(b.subscriptions, s)
+		subscriptions = append(subscriptions, s)
+	}
+
+	b.mutex.Unlock()
+
+	for _, s := range subscriptions {
+		s.close()
+	}
+}
+
+func (b *Broadcaster) publish(ctx context.Context, sentence Sentence) {
+	// send outside the lock, so a blocked subscriber does not block the
+	// Subscribe and Unsubscribe calls.
+	b.mutex.Lock()
+
+	var subscriptions []*Subscription
+	for s := range b.subscriptions {
+		if s.accepts(sentence) {
+			subscriptions = append(subscriptions, s)
+		}
+	}
+
+	b.mutex.Unlock()
+
+	for _, s := range subscriptions {
+		s.send(ctx, sentence)
+	}
+}
+
+func (s *Subscription) accepts(sentence Sentence) bool {
+	if s.types == nil {
+		return true
+	}
+
+	sentenceType := sentence.Type()
+
+	return s.types[sentenceType] || s.types[string(sentence.TalkerID())+sentenceType]
+}
+
+func (s *Subscription) send(ctx context.Context, sentence Sentence) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// it was unsubscribed after being picked by publish.
+	if s.closed {
+		return
+	}
+
+	switch s.policy {
+	case Block:
+		select {
+		case s.c <- sentence:
+		case <-s.done:
+		case <-ctx.Done():
+		}
+
+	case DropNewest:
+		select {
+		case s.c <- sentence:
+		default:
+			s.dropped.Add(1)
+		}
+
+	default:
+		for {
+			select {
+			case s.c <- sentence:
+				return
+			default:
+			}
+
+			select {
+			case <-s.c:
+				s.dropped.Add(1)
+			default:
+			}
+		}
+	}
+}
+
+// sends the parsed sentences to the broadcaster.
+type broadcastHandler struct {
+	ctx         context.Context
+	broadcaster *Broadcaster
+}
+
+func (h broadcastHandler) OnBeforeParse(sentenceType, sentence string) bool {
+	return true
+}
+
+func (h broadcastHandler) OnAfterParse(sentenceType, sentence string, err error) {}
+
+func (h broadcastHandler) OnSentence(sentence Sentence) {
+	h.broadcaster.publish(h.ctx, sentence)
+}
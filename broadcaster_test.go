@@ -0,0 +1,133 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+var broadcasterSentences = strings.Join([]string{
+	"$GPGGA,064951.000,2307.1256,N,12016.4438,E,1,8,0.95,39.9,M,17.8,M,,*63",
+	"$GPRMC,064951.000,A,2307.1256,N,12016.4438,E,0.03,165.48,260406,,,A*63",
+	"$GNGGA,064952.000,2307.1256,N,12016.4438,E,1,12,0.95,39.9,M,17.8,M,,*45",
+	"$GPGGA,064953.000,2307.1256,N,12016.4438,E,1,8,0.95,39.9,M,17.8,M,,*61",
+}, "\r\n")
+
+// returns the raw sentences received until the channel is closed.
+func receiveAll(c <-chan Sentence) []string {
+	var result []string
+	for s := range c {
+		result = append(result, s.Raw()[:6])
+	}
+	return result
+}
+
+func TestBroadcaster(t *testing.T) {
+	b := NewBroadcaster(strings.NewReader(broadcasterSentences))
+
+	all := b.Subscribe(SubscribeOptions{})
+	gga := b.Subscribe(SubscribeOptions{Types: []string{"GGA"}})
+	gpgga := b.Subscribe(SubscribeOptions{Types: []string{"GPGGA"}})
+
+	if err := b.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error `%v`", err)
+	}
+
+	tests := []struct {
+		name         string
+		subscription *Subscription
+		expected     string
+	}{
+		{"all", all, "$GPGGA,$GPRMC,$GNGGA,$GPGGA"},
+		{"GGA", gga, "$GPGGA,$GNGGA,$GPGGA"},
+		{"GPGGA", gpgga, "$GPGGA,$GPGGA"},
+	}
+
+	for _, test := range tests {
+		actual := strings.Join(receiveAll(test.subscription.C), ",")
+		if actual != test.expected {
+			t.Errorf("%s: expected %s but got %s", test.name, test.expected, actual)
+		}
+	}
+
+	// subscribing after the broadcaster stopped returns a closed channel.
+	if _, ok := <-b.Subscribe(SubscribeOptions{}).C; ok {
+		t.Errorf("expected a closed channel")
+	}
+}
+
+func TestBroadcasterDropPolicies(t *testing.T) {
+	b := NewBroadcaster(strings.NewReader(broadcasterSentences))
+
+	dropOldest := b.Subscribe(SubscribeOptions{BufferSize: 1, Policy: DropOldest})
+	dropNewest := b.Subscribe(SubscribeOptions{BufferSize: 1, Policy: DropNewest})
+
+	b.Run(context.Background())
+
+	sentences := strings.Split(broadcasterSentences, "\r\n")
+
+	if s := <-dropOldest.C; s.Raw() != sentences[3] || dropOldest.Dropped() != 3 {
+		t.Errorf("DropOldest: expected the last sentence and 3 dropped but got `%s` and %d dropped", s.Raw(), dropOldest.Dropped())
+	}
+
+	if s := <-dropNewest.C; s.Raw() != sentences[0] || dropNewest.Dropped() != 3 {
+		t.Errorf("DropNewest: expected the first sentence and 3 dropped but got `%s` and %d dropped", s.Raw(), dropNewest.Dropped())
+	}
+}
+
+func TestBroadcasterBlock(t *testing.T) {
+	b := NewBroadcaster(strings.NewReader(broadcasterSentences))
+
+	block := b.Subscribe(SubscribeOptions{BufferSize: 1, Policy: Block})
+	blocked := b.Subscribe(SubscribeOptions{BufferSize: 1, Policy: Block})
+
+	result := make(chan error)
+
+	go func() {
+		result <- b.Run(context.Background())
+	}()
+
+	received := make(chan []string)
+
+	go func() {
+		received <- receiveAll(block.C)
+	}()
+
+	// wait for the broadcaster to block on the subscription that is not being
+	// received, then unsubscribe it to unblock the broadcaster.
+	for len(blocked.C) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// the blocked subscription does not block the other subscribers.
+	subscribed := make(chan bool)
+
+	go func() {
+		b.Subscribe(SubscribeOptions{}).Unsubscribe()
+		subscribed <- true
+	}()
+
+	select {
+	case <-subscribed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Subscribe blocked while the broadcaster was blocked on a subscription")
+	}
+
+	blocked.Unsubscribe()
+
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Errorf("unexpected error `%v`", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not stop after the blocked subscription was cancelled")
+	}
+
+	if actual := strings.Join(<-received, ","); actual != "$GPGGA,$GPRMC,$GNGGA,$GPGGA" {
+		t.Errorf("expected all the sentences but got %s", actual)
+	}
+}
@@ -0,0 +1,199 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"fmt"
+	"time"
+)
+
+// DatePolicy resolves the dates of the sentences.
+type DatePolicy struct {
+	// CenturyPivot is the first two-digit year of the 1900s. e.g. with 80 the
+	// years 80 to 99 are 1980 to 1999 and the years 00 to 79 are 2000 to 2079.
+	// 0 puts all the two-digit years in the 2000s.
+	CenturyPivot int
+
+	// NotBefore is the earliest valid date. The earlier dates are corrected
+	// by adding 1024 weeks until they are not before NotBefore, which undoes
+	// the GPS week rollover of the receivers that report dates ~19.6 years
+	// in the past. The zero value disables the correction.
+	NotBefore time.Time
+}
+
+// DefaultDatePolicy returns the policy used to parse the dates when no
+// WithDatePolicy option is given: the years 80 to 99 are 1980 to 1999 and
+// the dates are not corrected for the GPS week rollover.
+func DefaultDatePolicy() DatePolicy {
+	return DatePolicy{
+		CenturyPivot: 80,
+	}
+}
+
+// ParseOption configures how Parse, Sentences, Visit, VisitContext and
+// Broadcaster parse the sentences.
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	datePolicy DatePolicy
+}
+
+func newParseOptions(options []ParseOption) parseOptions {
+	result := parseOptions{
+		datePolicy: DefaultDatePolicy(),
+	}
+
+	for _, option := range options {
+		option(&result)
+	}
+
+	return result
+}
+
+// WithDatePolicy resolves the dates of the sentences (e.g. GPRMC and GPZDA)
+// with policy instead of the DefaultDatePolicy. The policy is also given to
+// the parsers registered with RegisterParser.
+func WithDatePolicy(policy DatePolicy) ParseOption {
+	return func(o *parseOptions) {
+		o.datePolicy = policy
+	}
+}
+
+// GPS week rollover period.
+const gpsWeekRollover = 1024 * 7 * 24 * time.Hour
+
+// returns the four-digit year of a two-digit year.
+func (p *DatePolicy) year(y int) int {
+	if p.CenturyPivot > 0 && y >= p.CenturyPivot {
+		return 1900 + y
+	}
+	return 2000 + y
+}
+
+// Date returns the UTC midnight of the date, e.g. to parse the dates of the
+// sentences registered with RegisterParser. A two-digit year (i.e. less than
+// 100) is resolved by the CenturyPivot, and the date is corrected for the GPS
+// week rollover.
+//
+// Returns an error when the date does not exist, e.g. February 31.
+func (p *DatePolicy) Date(year, month, day int) (time.Time, error) {
+	if year < 100 {
+		year = p.year(year)
+	}
+
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+
+	// time.Date normalizes the dates that do not exist, e.g. February 31 is
+	// March 3.
+	if date.Day() != day || date.Month() != time.Month(month) {
+		return time.Time{}, fmt.Errorf("Failed to parse date %04d-%02d-%02d: the day is not in the month", year, month, day)
+	}
+
+	return p.correct(date), nil
+}
+
+// returns the date corrected for the GPS week rollover.
+func (p *DatePolicy) correct(date time.Time) time.Time {
+	if p.NotBefore.IsZero() {
+		return date
+	}
+
+	for date.Before(p.NotBefore) {
+		date = date.Add(gpsWeekRollover)
+	}
+
+	return date
+}
+
+// DateTracker turns the times of day of the sentences without a date (e.g.
+// GPGGA) into full UTC times, using the last known date, e.g.:
+//
+//	var dates nmea.DateTracker
+//	for s, err := range nmea.Sentences(f) {
+//		if err != nil {
+//			continue
+//		}
+//		dates.Add(s)
+//		if s, ok := s.(*nmea.GPGGA); ok {
+//			t, ok := dates.Time(s.Time)
+//			...
+//		}
+//	}
+//
+// The date is set by the GPRMC and GPZDA sentences, or by SetDate when the stream has
+// no sentence with a date. The date is advanced when the time of day goes
+// back by more than half a day, i.e. when the UTC midnight was crossed since
+// the last sentence.
+//
+// The zero value is ready to use.
+type DateTracker struct {
+	date      time.Time     // UTC midnight of the current date. zero when unknown.
+	last      time.Duration // time of day of the last sentence.
+	lastValid bool
+}
+
+// SetDate sets the current date. Only the year, month and day of date (in
+// UTC) are used.
+func (t *DateTracker) SetDate(date time.Time) {
+	date = date.UTC()
+
+	t.date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	t.lastValid = false
+}
+
+// Add updates the current date from the sentence. GPRMC and GPZDA set the
+// date, and GPGGA, GPGLL, GNGNS and GPGST detect the midnight rollover. The other sentence
+// types are ignored.
+func (t *DateTracker) Add(sentence Sentence) {
+	switch s := sentence.(type) {
+	case *GPRMC:
+		t.setTime(s.Time)
+	case *GPZDA:
+		t.setTime(s.Time)
+	case *GPGGA:
+		t.Time(s.Time)
+	case *GPGLL:
+		t.Time(s.Time)
+	case *GNGNS:
+		t.Time(s.Time)
+	case *GPGST:
+		t.Time(s.Time)
+	}
+}
+
+// Time returns the UTC time of the given time of day, and whether the date
+// is known. A time of day that is more than half a day before the previous
+// one advances the date to the next day. A time of day that is more than half
+// a day after the previous one is a late sentence from the previous day.
+func (t *DateTracker) Time(timeOfDay time.Duration) (time.Time, bool) {
+	if t.date.IsZero() {
+		return time.Time{}, false
+	}
+
+	date := t.date
+
+	switch {
+	case !t.lastValid:
+		t.last = timeOfDay
+		t.lastValid = true
+
+	case timeOfDay < t.last-12*time.Hour:
+		t.date = t.date.AddDate(0, 0, 1)
+		t.last = timeOfDay
+		date = t.date
+
+	case timeOfDay > t.last+12*time.Hour:
+		date = date.AddDate(0, 0, -1)
+
+	default:
+		t.last = timeOfDay
+	}
+
+	return date.Add(timeOfDay), true
+}
+
+func (t *DateTracker) setTime(value time.Time) {
+	t.SetDate(value)
+	t.last = timeOfDay(value.UTC())
+	t.lastValid = true
+}
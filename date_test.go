@@ -0,0 +1,175 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDateTracker(t *testing.T) {
+	var dates DateTracker
+
+	if _, ok := dates.Time(duration("23h59m59s")); ok {
+		t.Errorf("expected an unknown date")
+	}
+
+	dates.SetDate(time.Date(2024, 12, 31, 15, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		timeOfDay time.Duration
+		expected  time.Time
+	}{
+		{duration("23h59m59s900ms"), time.Date(2024, 12, 31, 23, 59, 59, 900000000, time.UTC)},
+		// the midnight rollover.
+		{duration("0s"), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{duration("100ms"), time.Date(2025, 1, 1, 0, 0, 0, 100000000, time.UTC)},
+		// a late sentence from the previous day.
+		{duration("23h59m59s950ms"), time.Date(2024, 12, 31, 23, 59, 59, 950000000, time.UTC)},
+		{duration("200ms"), time.Date(2025, 1, 1, 0, 0, 0, 200000000, time.UTC)},
+	}
+
+	for _, test := range tests {
+		actual, ok := dates.Time(test.timeOfDay)
+		if !ok || !actual.Equal(test.expected) {
+			t.Errorf("%v expected to be %v but it's actually %v %v", test.timeOfDay, test.expected, actual, ok)
+		}
+	}
+}
+
+func TestDateTrackerAdd(t *testing.T) {
+	var dates DateTracker
+
+	dates.Add(&GPRMC{Time: time.Date(2006, 4, 26, 23, 59, 59, 0, time.UTC)})
+
+	// a GGA-only stream across the midnight.
+	for _, s := range []string{"23h59m59s500ms", "0s", "500ms"} {
+		dates.Add(&GPGGA{Time: duration(s)})
+	}
+
+	actual, _ := dates.Time(duration("1s"))
+
+	expected := time.Date(2006, 4, 27, 0, 0, 1, 0, time.UTC)
+
+	if !actual.Equal(expected) {
+		t.Errorf("expected %v but it's actually %v", expected, actual)
+	}
+}
+
+func TestDatePolicy(t *testing.T) {
+	notBefore := time.Date(2019, 4, 7, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		options  []ParseOption
+		sentence string
+		expected time.Time
+	}{
+		// by default the years 80 to 99 are in the 1900s. before DatePolicy
+		// all the years were in the 2000s, i.e. 99 was 2099.
+		{nil, "$GPRMC,000000.000,V,,,,,0.00,0.00,260499,,,N*", time.Date(1999, 4, 26, 0, 0, 0, 0, time.UTC)},
+		{nil, "$GPRMC,000000.000,V,,,,,0.00,0.00,260480,,,N*", time.Date(1980, 4, 26, 0, 0, 0, 0, time.UTC)},
+		{nil, "$GPRMC,000000.000,V,,,,,0.00,0.00,260479,,,N*", time.Date(2079, 4, 26, 0, 0, 0, 0, time.UTC)},
+		// a leap day.
+		{nil, "$GPRMC,000000.000,V,,,,,0.00,0.00,290204,,,N*", time.Date(2004, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{nil, "$GPZDA,000000.00,29,02,2024,,*", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{[]ParseOption{WithDatePolicy(DatePolicy{})}, "$GPRMC,000000.000,V,,,,,0.00,0.00,260499,,,N*", time.Date(2099, 4, 26, 0, 0, 0, 0, time.UTC)},
+		{[]ParseOption{WithDatePolicy(DatePolicy{CenturyPivot: 80})}, "$GPRMC,000000.000,V,,,,,0.00,0.00,260499,,,N*", time.Date(1999, 4, 26, 0, 0, 0, 0, time.UTC)},
+		{[]ParseOption{WithDatePolicy(DatePolicy{CenturyPivot: 80})}, "$GPRMC,000000.000,V,,,,,0.00,0.00,260479,,,N*", time.Date(2079, 4, 26, 0, 0, 0, 0, time.UTC)},
+		{[]ParseOption{WithDatePolicy(DatePolicy{CenturyPivot: 90})}, "$GPRMC,000000.000,V,,,,,0.00,0.00,260485,,,N*", time.Date(2085, 4, 26, 0, 0, 0, 0, time.UTC)},
+		// the GPS week rollover.
+		{[]ParseOption{WithDatePolicy(DatePolicy{NotBefore: notBefore})}, "$GPRMC,000000.000,V,,,,,0.00,0.00,030202,,,N*", time.Date(2021, 9, 19, 0, 0, 0, 0, time.UTC)},
+		{[]ParseOption{WithDatePolicy(DatePolicy{NotBefore: notBefore})}, "$GPRMC,000000.000,V,,,,,0.00,0.00,190921,,,N*", time.Date(2021, 9, 19, 0, 0, 0, 0, time.UTC)},
+		{nil, "$GPZDA,000000.00,03,02,2002,,*", time.Date(2002, 2, 3, 0, 0, 0, 0, time.UTC)},
+		{[]ParseOption{WithDatePolicy(DatePolicy{NotBefore: notBefore})}, "$GPZDA,000000.00,03,02,2002,,*", time.Date(2021, 9, 19, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		sentence := test.sentence + checksum(test.sentence)
+
+		s, err := Parse(sentence, test.options...)
+		if err != nil {
+			t.Errorf("`%s` unexpected error `%v`", sentence, err)
+			continue
+		}
+
+		var actual time.Time
+
+		switch s := s.(type) {
+		case *GPRMC:
+			actual = s.Time
+		case *GPZDA:
+			actual = s.Time
+		}
+
+		if !actual.Equal(test.expected) {
+			t.Errorf("`%s` expected to be %v but it's actually %v", sentence, test.expected, actual)
+		}
+	}
+}
+
+// a sentence with just a ddmmyy date.
+type pxdat struct {
+	BaseSentence
+	Date time.Time
+}
+
+func TestDatePolicyRegisterParser(t *testing.T) {
+	registerTestParser(t, "PXDAT", func(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+		date, err := parseDate(fields[0], dates)
+		if err != nil {
+			return nil, err
+		}
+		return &pxdat{BaseSentence: base, Date: date}, nil
+	})
+
+	sentence := "$PXDAT,260499*"
+	sentence += checksum(sentence)
+
+	s, err := Parse(sentence, WithDatePolicy(DatePolicy{}))
+	if err != nil {
+		t.Fatalf("`%s` unexpected error `%v`", sentence, err)
+	}
+
+	if expected := time.Date(2099, 4, 26, 0, 0, 0, 0, time.UTC); !s.(*pxdat).Date.Equal(expected) {
+		t.Errorf("`%s` expected to be %v but it's actually %v", sentence, expected, s.(*pxdat).Date)
+	}
+}
+
+func TestDatePolicySentences(t *testing.T) {
+	sentence := "$GPRMC,000000.000,V,,,,,0.00,0.00,030202,,,N*"
+	sentence += checksum(sentence)
+
+	policy := WithDatePolicy(DatePolicy{NotBefore: time.Date(2019, 4, 7, 0, 0, 0, 0, time.UTC)})
+
+	for s, err := range Sentences(strings.NewReader(sentence), policy) {
+		if err != nil {
+			t.Fatalf("unexpected error `%v`", err)
+		}
+
+		expected := time.Date(2021, 9, 19, 0, 0, 0, 0, time.UTC)
+
+		if actual := s.(*GPRMC).Time; !actual.Equal(expected) {
+			t.Errorf("expected to be %v but it's actually %v", expected, actual)
+		}
+	}
+}
+
+func TestDateTrackerGPZDA(t *testing.T) {
+	var dates DateTracker
+
+	s, err := Parse("$GPZDA,235959.00,31,12,2024,,*" + checksum("$GPZDA,235959.00,31,12,2024,,*"))
+	if err != nil {
+		t.Fatalf("unexpected error `%v`", err)
+	}
+
+	dates.Add(s)
+
+	actual, _ := dates.Time(duration("1s"))
+
+	expected := time.Date(2025, 1, 1, 0, 0, 1, 0, time.UTC)
+
+	if !actual.Equal(expected) {
+		t.Errorf("expected %v but it's actually %v", expected, actual)
+	}
+}
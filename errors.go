@@ -0,0 +1,57 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import "fmt"
+
+// ChecksumError is reported when a sentence is malformed (e.g. it does not
+// start with $ or ! or does not end with *CC) or when its checksum does not
+// match.
+type ChecksumError struct {
+	Sentence string
+	Expected string // checksum that came with the sentence. e.g. 4C. empty when missing.
+	Actual   byte   // checksum computed from the sentence.
+}
+
+func (e *ChecksumError) Error() string {
+	if e.Expected == "" {
+		return fmt.Sprintf("Invalid sentence %q: malformed or missing checksum", e.Sentence)
+	}
+	return fmt.Sprintf("Invalid sentence %q: checksum %s does not match the computed %02X", e.Sentence, e.Expected, e.Actual)
+}
+
+// UnknownSentenceError is reported when there is no parser for a sentence type.
+type UnknownSentenceError struct {
+	SentenceType string
+}
+
+func (e *UnknownSentenceError) Error() string {
+	return fmt.Sprintf("Unknown sentence type %s", e.SentenceType)
+}
+
+// FieldError is reported when a sentence field cannot be parsed.
+type FieldError struct {
+	SentenceType string
+	Index        int    // zero based index of the field (the type is not counted).
+	Name         string // e.g. Latitude.
+	Value        string // raw field value.
+	Err          error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("Failed to parse %s field %d (%s) %q: %v", e.SentenceType, e.Index, e.Name, e.Value, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+func newFieldError(sentenceType string, fields []string, index int, name string, err error) *FieldError {
+	return &FieldError{
+		SentenceType: sentenceType,
+		Index:        index,
+		Name:         name,
+		Value:        fields[index],
+		Err:          err,
+	}
+}
@@ -0,0 +1,512 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Fix is a position epoch, that is, the merge of all the sentences that
+// describe the receiver state at a given UTC time.
+//
+// NB The optional fields are not Valid when no sentence of the epoch had them.
+type Fix struct {
+	Time                time.Time         // UTC date and time. zero when the date is still unknown. See FixAggregator.SetDate.
+	TimeOfDay           time.Duration     // UTC time since midnight.
+	Status              byte              // GPRMC or GPGLL status. A=data valid; V=data not valid. 0 when the epoch had neither.
+	PositionFix         FixQuality        // GPGGA position fix.
+	Mode                byte              // GPRMC, GPGLL or GPVTG mode. 0 when not available.
+	Modes               GNSModes          // GNGNS mode of each constellation. nil when the epoch had no GNGNS.
+	Mode2               byte              // GPGSA mode. 1=No fix; 2=2D; 3=3D. 0 when the epoch had no GPGSA.
+	Latitude            Optional[float64] // in degrees. negative is south.
+	Longitude           Optional[float64] // in degrees. negative is west.
+	CoordinatePrecision int               // number of decimal digits of the Latitude and Longitude minutes in the sentence.
+	Altitude            Optional[float32] // in meters. above/below the mean-sea-level (geoid).
+	GeoidSeparation     Optional[float32] // in meters. height of the geoid above the WGS84 ellipsoid.
+	Speed               Optional[float32] // in knots.
+	Course              Optional[float32] // in degrees. true course over ground.
+	MagneticVariation   Optional[float32] // in degrees. east is positive and west is negative.
+	HorizontalError     Optional[float32] // in meters. GPGST 1-sigma horizontal position error. See GPGST.HorizontalError.
+	VerticalError       Optional[float32] // in meters. GPGST 1-sigma altitude error.
+	PDOP                Optional[float32]
+	HDOP                Optional[float32]
+	VDOP                Optional[float32]
+	UsedSatellites      byte           // number of satellites used in the fix.
+	SVs                 []byte         // IDs of the satellites used in the fix, from all the GPGSA of the epoch.
+	Satellites          []GSVSatellite // satellites in view, from all the GPGSV of the epoch.
+	Sentences           []Sentence     // sentences merged into this fix, in the order they were added.
+}
+
+// FixAggregator merges the sentences of each position epoch into a single
+// Fix, e.g.:
+//
+//	aggregator := nmea.NewFixAggregator(1500*time.Millisecond, func(fix *nmea.Fix) {
+//		...
+//	})
+//	for s, err := range nmea.Sentences(f) {
+//		if err == nil {
+//			aggregator.Add(s)
+//		}
+//	}
+//	aggregator.Flush()
+//
+// The epochs are delimited by the UTC time of the GPGGA, GPRMC, GPGLL, GNGNS
+// and GPGST sentences.
+// The sentences without a time (GPGSA, GPGSV and GPVTG) are merged into the
+// current epoch. A Fix is emitted when a sentence of the next epoch is added,
+// when no sentence is added during the timeout, or when Flush is called.
+//
+// Some receivers send the sentences without a time before the timed sentences
+// of their epoch (e.g. GPGSA before GPGGA). So, a sentence without a time
+// whose type (and talker) the current epoch already has from an earlier group
+// of sentences is buffered until the next timed sentence decides its epoch:
+// the current one when it has the same time, or the next one otherwise.
+//
+// The date of the epochs without a GPRMC is tracked like in DateTracker, e.g.
+// from the GPZDA sentences.
+//
+// The onFix function is called without the aggregator lock, one Fix at a
+// time and in order, so it can call Add, Flush or SetDate. The fixes emitted
+// by those calls are delivered after onFix returns.
+type FixAggregator struct {
+	timeout time.Duration
+	onFix   func(fix *Fix)
+
+	mutex      sync.Mutex
+	pending    *fixEpoch
+	buffered   []Sentence // sentences without a time of an undecided epoch.
+	generation uint64
+	timer      *time.Timer
+	assembler  GPGSVAssembler
+	dates      DateTracker
+	fixes      []*Fix // emitted fixes not yet delivered to onFix.
+	delivering bool   // whether a goroutine is calling onFix.
+}
+
+// the epoch being assembled.
+type fixEpoch struct {
+	fix     *Fix
+	timed   bool // whether the fix TimeOfDay is known.
+	gga     bool
+	rmc     bool
+	gns     bool
+	vtg     bool
+	gsaDOPs bool
+}
+
+// NewFixAggregator returns an aggregator that calls onFix with each merged
+// Fix. A timeout of 0 disables the timeout, in which case the last epoch is
+// only emitted by Flush.
+func NewFixAggregator(timeout time.Duration, onFix func(fix *Fix)) *FixAggregator {
+	return &FixAggregator{
+		timeout: timeout,
+		onFix:   onFix,
+	}
+}
+
+// Add merges the sentence into the current epoch. The "message N of M"
+// *GPGSVMessage sentences returned by Parse are assembled before being
+// merged. The sentence types that do not describe a position epoch are
+// ignored.
+func (a *FixAggregator) Add(sentence Sentence) {
+	a.mutex.Lock()
+	defer a.deliver()
+
+	if message, ok := sentence.(*GPGSVMessage); ok {
+		gpgsv := a.assembler.Add(message)
+		if gpgsv == nil {
+			return
+		}
+		sentence = gpgsv
+	}
+
+	if !a.merge(sentence) {
+		return
+	}
+
+	if a.timeout > 0 {
+		a.generation++
+		generation := a.generation
+
+		if a.timer != nil {
+			a.timer.Stop()
+		}
+
+		a.timer = time.AfterFunc(a.timeout, func() {
+			a.mutex.Lock()
+			defer a.deliver()
+
+			// ignore a timer that was superseded by a later Add.
+			if a.generation == generation {
+				a.emit()
+			}
+		})
+	}
+}
+
+// SetDate sets the current date, for the streams without a GPRMC. See
+// DateTracker.SetDate.
+func (a *FixAggregator) SetDate(date time.Time) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.dates.SetDate(date)
+}
+
+// Flush emits the current epoch, if any.
+func (a *FixAggregator) Flush() {
+	a.mutex.Lock()
+	defer a.deliver()
+
+	if a.timer != nil {
+		a.timer.Stop()
+		a.timer = nil
+	}
+
+	a.generation++
+
+	a.emit()
+}
+
+// calls onFix with the emitted fixes, and unlocks the aggregator. must be
+// called with the aggregator locked.
+//
+// only one goroutine calls onFix at a time. the fixes emitted while it's
+// calling onFix (e.g. by an Add from onFix or by the timer) are queued and
+// delivered by it, in order.
+func (a *FixAggregator) deliver() {
+	if a.delivering {
+		a.mutex.Unlock()
+		return
+	}
+
+	a.delivering = true
+
+	for len(a.fixes) > 0 {
+		fix := a.fixes[0]
+		a.fixes = a.fixes[1:]
+
+		a.mutex.Unlock()
+		a.onFix(fix)
+		a.mutex.Lock()
+	}
+
+	a.delivering = false
+	a.fixes = nil
+
+	a.mutex.Unlock()
+}
+
+// emits the current epoch, and the buffered sentences as an epoch of their
+// own, as no timed sentence will decide it.
+func (a *FixAggregator) emit() {
+	a.emitPending()
+
+	if len(a.buffered) > 0 {
+		a.mergeBuffered()
+		a.emitPending()
+	}
+}
+
+func (a *FixAggregator) emitPending() {
+	if a.pending == nil {
+		return
+	}
+
+	fix := a.pending.fix
+
+	if fix.Time.IsZero() && a.pending.timed {
+		fix.Time, _ = a.dates.Time(fix.TimeOfDay)
+	}
+
+	a.pending = nil
+
+	a.fixes = append(a.fixes, fix)
+}
+
+// merges the buffered sentences into the current epoch, starting a new one
+// when there is none.
+func (a *FixAggregator) mergeBuffered() {
+	buffered := a.buffered
+	a.buffered = nil
+
+	for _, s := range buffered {
+		a.untimedEpoch().mergeUntimed(s)
+	}
+}
+
+// returns the epoch of a sentence with the given time of day, emitting the
+// current epoch when the sentence belongs to the next one. the buffered
+// sentences are merged into the returned epoch.
+func (a *FixAggregator) epoch(timeOfDay time.Duration) *fixEpoch {
+	if a.pending != nil && a.pending.timed && a.pending.fix.TimeOfDay != timeOfDay {
+		a.emitPending()
+	}
+
+	a.mergeBuffered()
+
+	e := a.untimedEpoch()
+
+	if !e.timed {
+		e.timed = true
+		e.fix.TimeOfDay = timeOfDay
+	}
+
+	return e
+}
+
+// returns the current epoch, starting a new one when there is none.
+func (a *FixAggregator) untimedEpoch() *fixEpoch {
+	if a.pending == nil {
+		a.pending = &fixEpoch{fix: &Fix{}}
+	}
+	return a.pending
+}
+
+// merges the sentence into its epoch. returns whether the sentence type is
+// merged.
+func (a *FixAggregator) merge(sentence Sentence) bool {
+	var e *fixEpoch
+
+	switch s := sentence.(type) {
+	case *GPGGA:
+		e = a.epoch(s.Time)
+		if !e.gga {
+			e.gga = true
+			e.mergeGPGGA(s)
+		}
+
+	case *GPRMC:
+		e = a.epoch(timeOfDay(s.Time))
+		if !e.rmc {
+			e.rmc = true
+			e.mergeGPRMC(s)
+		}
+
+	case *GPGLL:
+		e = a.epoch(s.Time)
+		e.mergeGPGLL(s)
+
+	case *GNGNS:
+		e = a.epoch(s.Time)
+		if !e.gns {
+			e.gns = true
+			e.mergeGNGNS(s)
+		}
+
+	case *GPGST:
+		e = a.epoch(s.Time)
+		e.mergeGPGST(s)
+
+	case *GPGSA, *GPGSV, *GPVTG:
+		if a.pending != nil && (len(a.buffered) > 0 || a.pending.startsNextGroup(sentence)) {
+			a.buffered = append(a.buffered, sentence)
+		} else {
+			a.untimedEpoch().mergeUntimed(sentence)
+		}
+		return true
+
+	default:
+		// e.g. the GPZDA date.
+		a.dates.Add(sentence)
+		return false
+	}
+
+	e.fix.Sentences = append(e.fix.Sentences, sentence)
+
+	a.dates.Add(sentence)
+
+	return true
+}
+
+// merges a sentence without a time.
+func (e *fixEpoch) mergeUntimed(sentence Sentence) {
+	switch s := sentence.(type) {
+	case *GPGSA:
+		e.mergeGPGSA(s)
+
+	case *GPGSV:
+		e.fix.Satellites = append(e.fix.Satellites, s.Satellites...)
+
+	case *GPVTG:
+		if !e.vtg {
+			e.vtg = true
+			e.mergeGPVTG(s)
+		}
+	}
+
+	e.fix.Sentences = append(e.fix.Sentences, sentence)
+}
+
+// returns whether the sentence without a time repeats a group of sentences
+// that the timed epoch already has, i.e. it's of the same kind of an earlier
+// sentence of the epoch, but not of the last one. e.g. the GPGSA of the next
+// epoch sent before its GPGGA. the consecutive sentences of the same kind,
+// e.g. the GNGSA of each constellation, are a single group.
+func (e *fixEpoch) startsNextGroup(sentence Sentence) bool {
+	sentences := e.fix.Sentences
+
+	if !e.timed || len(sentences) == 0 {
+		return false
+	}
+
+	kind := untimedKind(sentence)
+
+	if untimedKind(sentences[len(sentences)-1]) == kind {
+		return false
+	}
+
+	for _, s := range sentences {
+		if untimedKind(s) == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// returns the type, talker and system (or signal) of a sentence without a
+// time, or an empty string for the other sentences.
+func untimedKind(sentence Sentence) string {
+	switch s := sentence.(type) {
+	case *GPGSA:
+		return fmt.Sprintf("%s%s%v", s.Talker, s.Type(), s.SystemID)
+	case *GPGSV:
+		return fmt.Sprintf("%s%s%d", s.Talker, s.Type(), s.SignalID)
+	case *GPVTG:
+		return string(s.Talker) + s.Type()
+	}
+	return ""
+}
+
+func (e *fixEpoch) mergeGPGGA(s *GPGGA) {
+	f := e.fix
+
+	f.PositionFix = s.PositionFix
+	f.UsedSatellites = s.UsedSatellites
+	f.Altitude = s.Altitude
+	f.GeoidSeparation = s.GeoidSeparation
+
+	// GPGGA has precedence over GPGSA.
+	if s.HDOP.Valid {
+		f.HDOP = s.HDOP
+	}
+
+	e.mergePosition(s.Latitude, s.Longitude, s.CoordinatePrecision)
+}
+
+func (e *fixEpoch) mergeGPRMC(s *GPRMC) {
+	f := e.fix
+
+	f.Time = s.Time
+	f.Status = s.Status
+	f.MagneticVariation = s.MagneticVariation
+
+	if s.Mode != 0 {
+		f.Mode = s.Mode
+	}
+
+	// GPRMC has precedence over GPVTG.
+	if s.Speed.Valid {
+		f.Speed = s.Speed
+	}
+	if s.Heading.Valid {
+		f.Course = s.Heading
+	}
+
+	e.mergePosition(s.Latitude, s.Longitude, s.CoordinatePrecision)
+}
+
+// multi-constellation receivers send GNGNS instead of (or besides) GPGGA.
+func (e *fixEpoch) mergeGNGNS(s *GNGNS) {
+	f := e.fix
+
+	f.Modes = s.Modes
+
+	if !e.gga {
+		f.UsedSatellites = s.UsedSatellites
+		f.Altitude = s.Altitude
+		f.GeoidSeparation = s.GeoidSeparation
+		if s.HDOP.Valid {
+			f.HDOP = s.HDOP
+		}
+	}
+
+	e.mergePosition(s.Latitude, s.Longitude, s.CoordinatePrecision)
+}
+
+// the GPGST errors are an estimate of the fix accuracy.
+func (e *fixEpoch) mergeGPGST(s *GPGST) {
+	f := e.fix
+
+	f.HorizontalError = s.HorizontalError()
+	f.VerticalError = s.AltitudeError
+}
+
+// GPGLL is the only position sentence of some older instruments.
+func (e *fixEpoch) mergeGPGLL(s *GPGLL) {
+	f := e.fix
+
+	if f.Status == 0 {
+		f.Status = s.Status
+	}
+	if f.Mode == 0 {
+		f.Mode = s.Mode
+	}
+
+	e.mergePosition(s.Latitude, s.Longitude, s.CoordinatePrecision)
+}
+
+// a multi-constellation receiver sends one GPGSA for each constellation,
+// all with the same DOPs.
+func (e *fixEpoch) mergeGPGSA(s *GPGSA) {
+	f := e.fix
+
+	f.SVs = append(f.SVs, s.SVs...)
+
+	if f.Mode2 == 0 {
+		f.Mode2 = s.Mode2
+	}
+
+	if !e.gsaDOPs {
+		e.gsaDOPs = true
+		f.PDOP = s.PDOP
+		f.VDOP = s.VDOP
+		if !f.HDOP.Valid {
+			f.HDOP = s.HDOP
+		}
+	}
+
+	if !e.gga {
+		f.UsedSatellites = byte(len(f.SVs))
+	}
+}
+
+func (e *fixEpoch) mergeGPVTG(s *GPVTG) {
+	f := e.fix
+
+	if f.Mode == 0 {
+		f.Mode = s.Mode
+	}
+	if !f.Speed.Valid {
+		f.Speed = s.SpeedKnots
+	}
+	if !f.Course.Valid {
+		f.Course = s.TrueCourse
+	}
+}
+
+// the first sentence with a position wins.
+func (e *fixEpoch) mergePosition(latitude, longitude Optional[float64], precision int) {
+	f := e.fix
+
+	if f.Latitude.Valid || !latitude.Valid || !longitude.Valid {
+		return
+	}
+
+	f.Latitude = latitude
+	f.Longitude = longitude
+	f.CoordinatePrecision = precision
+}
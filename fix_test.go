@@ -0,0 +1,272 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var fixSentences = []string{
+	"$GPRMC,064951.000,A,2307.1256,N,12016.4438,E,0.03,165.48,260406,3.05,W,A*",
+	"$GPVTG,165.48,T,,M,0.03,N,0.06,K,A*",
+	"$GPGGA,064951.000,2307.1256,N,12016.4438,E,1,8,0.95,39.9,M,17.8,M,,*",
+	"$GPGSA,A,3,29,21,26,15,18,09,06,10,,,,,2.32,0.90,2.11*",
+	gpgsv1,
+	gpgsv2,
+	gpgsv3,
+	// the next epoch only has a GPGGA.
+	"$GPGGA,064952.000,2307.1257,N,12016.4439,E,1,8,0.95,40.0,M,17.8,M,,*",
+}
+
+// parses the sentences, computing the missing checksums.
+func parseAll(t *testing.T, sentences []string) []Sentence {
+	var result []Sentence
+	for _, sentence := range sentences {
+		if sentence[len(sentence)-1] == '*' {
+			sentence += checksum(sentence)
+		}
+		s, err := Parse(sentence)
+		if err != nil {
+			t.Fatalf("`%s` unexpected error `%v`", sentence, err)
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+func TestFixAggregator(t *testing.T) {
+	var fixes []*Fix
+
+	aggregator := NewFixAggregator(0, func(fix *Fix) {
+		fixes = append(fixes, fix)
+	})
+
+	sentences := parseAll(t, fixSentences)
+
+	for _, s := range sentences {
+		aggregator.Add(s)
+	}
+
+	if len(fixes) != 1 {
+		t.Fatalf("expected the first epoch to be emitted but got %d fixes", len(fixes))
+	}
+
+	aggregator.Flush()
+
+	if len(fixes) != 2 {
+		t.Fatalf("expected Flush to emit the last epoch but got %d fixes", len(fixes))
+	}
+
+	expected := &Fix{
+		Time:                time.Date(2006, 4, 26, 6, 49, 51, 0, time.UTC),
+		TimeOfDay:           duration("6h49m51s"),
+		Status:              'A',
+		PositionFix:         FixGPS,
+		Mode:                'A',
+		Mode2:               '3',
+		Latitude:            Some(23.11876),
+		Longitude:           Some(120.274063333333334),
+		CoordinatePrecision: 4,
+		Altitude:            Some[float32](39.9),
+		GeoidSeparation:     Some[float32](17.8),
+		Speed:               Some[float32](0.03),
+		Course:              Some[float32](165.48),
+		MagneticVariation:   Some[float32](-3.05),
+		PDOP:                Some[float32](2.32),
+		HDOP:                Some[float32](0.95),
+		VDOP:                Some[float32](2.11),
+		UsedSatellites:      8,
+		SVs:                 []byte{29, 21, 26, 15, 18, 9, 6, 10},
+		Satellites:          gpgsvSkyView.Satellites,
+	}
+
+	actual := fixes[0]
+
+	if len(actual.Sentences) != 5 {
+		t.Errorf("expected 5 merged sentences but got %d", len(actual.Sentences))
+	}
+	actual.Sentences = nil
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected `%+v` but got `%+v`", expected, actual)
+	}
+
+	// without a GPRMC the date of the previous epoch is used.
+	last := fixes[1]
+
+	if !last.Time.Equal(time.Date(2006, 4, 26, 6, 49, 52, 0, time.UTC)) || last.Altitude != Some[float32](40) {
+		t.Errorf("unexpected last fix `%+v`", last)
+	}
+}
+
+func TestFixAggregatorTimeout(t *testing.T) {
+	fixes := make(chan *Fix, 1)
+
+	aggregator := NewFixAggregator(10*time.Millisecond, func(fix *Fix) {
+		fixes <- fix
+	})
+
+	aggregator.Add(parseAll(t, fixSentences[2:3])[0])
+
+	select {
+	case fix := <-fixes:
+		if fix.TimeOfDay != duration("6h49m51s") {
+			t.Errorf("unexpected fix `%+v`", fix)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the epoch to be emitted after the timeout")
+	}
+
+	// without a GPRMC nor SetDate the date is unknown.
+	aggregator.Add(parseAll(t, fixSentences[7:8])[0])
+	aggregator.Flush()
+
+	if fix := <-fixes; !fix.Time.IsZero() {
+		t.Errorf("expected an unknown date but got %v", fix.Time)
+	}
+
+	// nothing is pending after Flush.
+	aggregator.Flush()
+
+	select {
+	case fix := <-fixes:
+		t.Errorf("unexpected fix `%+v`", fix)
+	default:
+	}
+}
+
+func TestFixAggregatorGPGSABeforeGPGGA(t *testing.T) {
+	var fixes []*Fix
+
+	aggregator := NewFixAggregator(0, func(fix *Fix) {
+		fixes = append(fixes, fix)
+	})
+
+	// a multi-constellation receiver that sends a GNGSA for each
+	// constellation before the GNGGA and GNRMC of each epoch.
+	sentences := parseAll(t, []string{
+		"$GNGSA,A,3,29,21,,,,,,,,,,,2.32,0.90,2.11*",
+		"$GNGSA,A,3,65,66,,,,,,,,,,,2.32,0.90,2.11*",
+		"$GNGGA,064951.000,2307.1256,N,12016.4438,E,1,4,0.90,39.9,M,17.8,M,,*",
+		"$GNRMC,064951.000,A,2307.1256,N,12016.4438,E,0.03,165.48,260406,,,A*",
+		"$GNGSA,A,3,26,15,,,,,,,,,,,1.50,0.80,1.20*",
+		"$GNGSA,A,3,67,68,,,,,,,,,,,1.50,0.80,1.20*",
+		"$GNGGA,064952.000,2307.1257,N,12016.4439,E,1,4,0.80,40.0,M,17.8,M,,*",
+		"$GNRMC,064952.000,A,2307.1257,N,12016.4439,E,0.03,165.48,260406,,,A*",
+		// buffered, then merged into the same epoch, as the next timed
+		// sentence has the same time.
+		"$GNGSA,A,3,27,16,,,,,,,,,,,1.40,0.70,1.10*",
+		"$GNGLL,2307.1257,N,12016.4439,E,064952.000,A,A*",
+		// buffered, then emitted as an epoch of its own by Flush.
+		"$GNGSA,A,3,28,17,,,,,,,,,,,1.30,0.60,1.00*",
+	})
+
+	for _, s := range sentences {
+		aggregator.Add(s)
+	}
+
+	aggregator.Flush()
+
+	if len(fixes) != 3 {
+		t.Fatalf("expected 3 fixes but got %d fixes", len(fixes))
+	}
+
+	expected := [][]byte{{29, 21, 65, 66}, {26, 15, 67, 68, 27, 16}, {28, 17}}
+
+	for i, fix := range fixes {
+		if !reflect.DeepEqual(fix.SVs, expected[i]) {
+			t.Errorf("fix %d expected the SVs %v but they are actually %v", i, expected[i], fix.SVs)
+		}
+	}
+
+	if fixes[1].TimeOfDay != duration("6h49m52s") || fixes[1].PDOP != Some[float32](1.5) {
+		t.Errorf("unexpected fix `%+v`", fixes[1])
+	}
+
+	if fixes[2].TimeOfDay != 0 || fixes[2].Latitude.Valid {
+		t.Errorf("expected a fix without a time but got `%+v`", fixes[2])
+	}
+}
+
+func TestFixAggregatorReentrant(t *testing.T) {
+	var fixes []*Fix
+
+	var aggregator *FixAggregator
+
+	sentences := parseAll(t, fixSentences)
+
+	aggregator = NewFixAggregator(0, func(fix *Fix) {
+		fixes = append(fixes, fix)
+		// the aggregator is not locked.
+		if len(fixes) == 1 {
+			aggregator.SetDate(time.Date(2006, 4, 27, 0, 0, 0, 0, time.UTC))
+			aggregator.Add(sentences[2])
+			aggregator.Flush()
+		}
+	})
+
+	for _, s := range sentences {
+		aggregator.Add(s)
+	}
+
+	aggregator.Flush()
+
+	// the fixes emitted from onFix are delivered after it returns, in order.
+	if len(fixes) != 3 {
+		t.Fatalf("expected 3 fixes but got %d fixes", len(fixes))
+	}
+
+	if fixes[1].TimeOfDay != duration("6h49m52s") || fixes[2].TimeOfDay != duration("6h49m51s") {
+		t.Errorf("unexpected fixes `%+v` `%+v`", fixes[1], fixes[2])
+	}
+}
+
+func TestFixAggregatorGPGLL(t *testing.T) {
+	var fixes []*Fix
+
+	aggregator := NewFixAggregator(0, func(fix *Fix) {
+		fixes = append(fixes, fix)
+	})
+
+	for _, s := range parseAll(t, []string{"$GPGLL,2307.1256,N,12016.4438,E,064951.000,A,A*", "$GPVTG,165.48,T,,M,0.03,N,0.06,K,A*"}) {
+		aggregator.Add(s)
+	}
+
+	aggregator.Flush()
+
+	if len(fixes) != 1 {
+		t.Fatalf("expected a fix but got %d fixes", len(fixes))
+	}
+
+	fix := fixes[0]
+
+	if fix.TimeOfDay != duration("6h49m51s") || fix.Status != 'A' || fix.Latitude != Some(23.11876) || fix.Course != Some[float32](165.48) {
+		t.Errorf("unexpected fix `%+v`", fix)
+	}
+}
+
+func TestFixAggregatorGPGST(t *testing.T) {
+	var fixes []*Fix
+
+	aggregator := NewFixAggregator(0, func(fix *Fix) {
+		fixes = append(fixes, fix)
+	})
+
+	for _, s := range parseAll(t, []string{fixSentences[2], "$GPGST,064951.000,2.3,1.7,1.2,35.5,3,4,3.1*"}) {
+		aggregator.Add(s)
+	}
+
+	aggregator.Flush()
+
+	if len(fixes) != 1 {
+		t.Fatalf("expected a fix but got %d fixes", len(fixes))
+	}
+
+	fix := fixes[0]
+
+	if fix.HorizontalError != Some[float32](5) || fix.VerticalError != Some[float32](3.1) {
+		t.Errorf("unexpected accuracy `%v` `%v`", fix.HorizontalError, fix.VerticalError)
+	}
+}
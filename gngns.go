@@ -0,0 +1,253 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type GNGNS struct {
+	BaseSentence
+	Time                time.Duration
+	Latitude            Optional[float64]
+	Longitude           Optional[float64]
+	CoordinatePrecision int // number of decimal digits of the Latitude and Longitude minutes in the sentence. e.g. 4 for ddmm.mmmm.
+	Modes               GNSModes
+	UsedSatellites      byte
+	HDOP                Optional[float32]
+	Altitude            Optional[float32] // in meters. orthometric height, i.e. above/below the mean-sea-level (geoid).
+	GeoidSeparation     Optional[float32] // in meters. height of the geoid above the WGS84 ellipsoid.
+	DGPSAge             Optional[float32] // in seconds. age of the differential corrections.
+	DGPSStationID       Optional[uint16]  // differential reference station ID. 0 to 1023.
+	NavigationalStatus  byte              // S=Safe; C=Caution; U=Unsafe; V=Not valid. 0 before NMEA 4.1.
+}
+
+// GNSModes is the mode of each constellation that contributed to a GNGNS
+// fix. e.g. AAN is {GP: A, GL: A, GA: N}.
+//
+// The modes are A=Autonomous mode; D=Differential mode; E=Estimated mode;
+// N=No fix; F=Float RTK mode; R=RTK mode; M=Manual input mode; S=Simulator
+// mode; P=Precise mode.
+type GNSModes map[TalkerID]byte
+
+// the constellations of the GNGNS mode indicator characters, in order.
+var gnsConstellations = []TalkerID{
+	TalkerGPS,
+	TalkerGLONASS,
+	TalkerGalileo,
+	TalkerBeiDou,
+	TalkerQZSS,
+	TalkerNavIC,
+}
+
+// Used returns the constellations that contributed to the fix, i.e. the ones
+// whose mode is not N, in the order of the mode indicator.
+func (m GNSModes) Used() []TalkerID {
+	var result []TalkerID
+	for _, constellation := range gnsConstellations {
+		if mode, ok := m[constellation]; ok && mode != 'N' {
+			result = append(result, constellation)
+		}
+	}
+	return result
+}
+
+// returns the modes as a mode indicator. e.g. AAN.
+func (m GNSModes) String() string {
+	l := 0
+	for i, constellation := range gnsConstellations {
+		if _, ok := m[constellation]; ok {
+			l = i + 1
+		}
+	}
+
+	var b strings.Builder
+
+	for _, constellation := range gnsConstellations[:l] {
+		mode, ok := m[constellation]
+		if !ok {
+			mode = 'N'
+		}
+		b.WriteByte(mode)
+	}
+
+	return b.String()
+}
+
+// GNSS Fix Data.
+//
+// Example:
+//
+//	$GNGNS,064951.000,2307.1256,N,12016.4438,E,AAN,12,0.95,39.9,17.8,,,V*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | UTC Time      | 064951.000 |         | hhmmss.sss                   |
+// |  1 | Latitude      | 2307.1256  |         | ddmm.mmmm                    |
+// |  2 | N/S indicator | N          |         | N=North or S=South           |
+// |  3 | Longitude     | 12016.4438 |         | dddmm.mmmm                   |
+// |  4 | E/W Indicator | E          |         | E=East or W=West             |
+// |  5 | Mode          | AAN        |         | One character for each       |
+// |    |               |            |         | constellation, in the order  |
+// |    |               |            |         | GPS, GLONASS, Galileo,       |
+// |    |               |            |         | BeiDou, QZSS and NavIC       |
+// |    |               |            |         | N=No fix                     |
+// |    |               |            |         | A=Autonomous mode            |
+// |    |               |            |         | D=Differential mode          |
+// |    |               |            |         | P=Precise mode               |
+// |    |               |            |         | R=RTK mode                   |
+// |    |               |            |         | F=Float RTK mode             |
+// |    |               |            |         | E=Estimated mode             |
+// |    |               |            |         | M=Manual input mode          |
+// |    |               |            |         | S=Simulator mode             |
+// |  6 | Satellites    | 12         |         | Range 0 to 99                |
+// |    | Used          |            |         |                              |
+// |  7 | HDOP          | 0.95       |         | Horizontal Dilution of       |
+// |    |               |            |         | Precision                    |
+// |  8 | Orthometric   | 39.9       | meters  | Altitude above mean-sea-level|
+// |    | Height        |            |         |                              |
+// |  9 | Geoidal       | 17.8       | meters  | Geoid-to-ellipsoid separation|
+// |    | Separation    |            |         |                              |
+// | 10 | Age of Diff.  |            | second  | Null fields when DGPS is not |
+// |    | Corr.         |            |         | used                         |
+// | 11 | Diff. Ref.    |            |         | 0000 to 1023                 |
+// |    | Station ID    |            |         |                              |
+// | 12 | Navigational  | V          |         | S=Safe                       |
+// |    | Status        |            |         | C=Caution                    |
+// |    |               |            |         | U=Unsafe                     |
+// |    |               |            |         | V=Not valid                  |
+// |    |               |            |         | Only in NMEA 4.1 and later   |
+// +----+---------------+------------+---------+------------------------------+
+func parseGNGNS(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &GNGNS{BaseSentence: base}
+
+	if len(fields) != 12 && len(fields) != 13 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	//
+	// time. e.g.: 064951.000 format: hhmmss.sss
+	timeMs, err := parseTime(fields[0])
+	if err != nil {
+		return nil, newFieldError(sentenceType, fields, 0, "UTC Time", err)
+	}
+
+	result.Time = time.Duration(timeMs) * time.Millisecond
+
+	//
+	// latitude.  if available. e.g.: 2307.1256  format: ddmm.mmmm
+	// longitude. if available. e.g.: 12016.4438 format: dddmm.mmmm
+	result.Latitude, result.Longitude, result.CoordinatePrecision, err = parsePosition(sentenceType, fields, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	//
+	// mode. e.g.: AAN
+	modes := fields[5]
+
+	if len(modes) == 0 || len(modes) > len(gnsConstellations) {
+		return nil, newFieldError(sentenceType, fields, 5, "Mode", fmt.Errorf("Failed to parse mode %s", modes))
+	}
+
+	result.Modes = make(GNSModes, len(modes))
+
+	for i := 0; i < len(modes); i++ {
+		if !isValidMode(modes[i]) {
+			return nil, newFieldError(sentenceType, fields, 5, "Mode", fmt.Errorf("Failed to parse mode %c", modes[i]))
+		}
+		result.Modes[gnsConstellations[i]] = modes[i]
+	}
+
+	//
+	// satellites used.
+	usedSatellites, err := strconv.ParseUint(fields[6], 10, 8)
+	if err != nil {
+		return nil, newFieldError(sentenceType, fields, 6, "Satellites Used", err)
+	}
+	result.UsedSatellites = byte(usedSatellites)
+
+	//
+	// HDOP, orthometric height, geoidal separation and age of diff. corr.
+	optionals := []struct {
+		index int
+		name  string
+		value *Optional[float32]
+	}{
+		{7, "HDOP", &result.HDOP},
+		{8, "Orthometric Height", &result.Altitude},
+		{9, "Geoidal Separation", &result.GeoidSeparation},
+		{10, "Age of Diff. Corr.", &result.DGPSAge},
+	}
+
+	for _, o := range optionals {
+		*o.value, err = parseOptionalFloat(sentenceType, fields, o.index, o.name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	//
+	// diff. ref. station ID.
+	if len(fields[11]) > 0 {
+		dgpsStationID, err := strconv.ParseUint(fields[11], 10, 16)
+		if err != nil || dgpsStationID > 1023 {
+			return nil, newFieldError(sentenceType, fields, 11, "Diff. Ref. Station ID", fmt.Errorf("Failed to parse station ID %s", fields[11]))
+		}
+		result.DGPSStationID = Some(uint16(dgpsStationID))
+	}
+
+	//
+	// navigational status. only in NMEA 4.1 and later.
+	if len(fields) == 13 {
+		switch fields[12] {
+		case "S", "C", "U", "V":
+			result.NavigationalStatus = fields[12][0]
+		default:
+			return nil, newFieldError(sentenceType, fields, 12, "Navigational Status", fmt.Errorf("Failed to parse navigational status %s", fields[12]))
+		}
+	}
+
+	return result, nil
+}
+
+func (GNGNS) Type() string {
+	return "GNS"
+}
+
+func (s *GNGNS) String() string {
+	return marshalString(s)
+}
+
+func (s *GNGNS) marshalFields(e *Encoder) []string {
+	fields := make([]string, 12)
+
+	fields[0] = e.formatTime(s.Time)
+
+	fields[1], fields[2], fields[3], fields[4] = e.formatPosition(s.Latitude, s.Longitude, s.CoordinatePrecision)
+
+	fields[5] = s.Modes.String()
+	fields[6] = fmt.Sprintf("%02d", s.UsedSatellites)
+	fields[7] = e.formatOptionalFloat(s.HDOP)
+	fields[8] = e.formatOptionalFloat(s.Altitude)
+	fields[9] = e.formatOptionalFloat(s.GeoidSeparation)
+	fields[10] = e.formatOptionalFloat(s.DGPSAge)
+
+	if s.DGPSStationID.Valid {
+		fields[11] = fmt.Sprintf("%04d", s.DGPSStationID.Value)
+	}
+
+	if s.NavigationalStatus != 0 {
+		fields = append(fields, formatByte(s.NavigationalStatus))
+	}
+
+	return fields
+}
@@ -0,0 +1,31 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGNSModes(t *testing.T) {
+	tests := []struct {
+		modes    GNSModes
+		used     []TalkerID
+		expected string
+	}{
+		{GNSModes{TalkerGPS: 'A', TalkerGLONASS: 'A', TalkerGalileo: 'N'}, []TalkerID{TalkerGPS, TalkerGLONASS}, "AAN"},
+		{GNSModes{TalkerGalileo: 'D'}, []TalkerID{TalkerGalileo}, "NND"},
+		{GNSModes{TalkerGPS: 'N'}, nil, "N"},
+		{GNSModes{}, nil, ""},
+	}
+
+	for _, test := range tests {
+		if actual := test.modes.Used(); !reflect.DeepEqual(test.used, actual) {
+			t.Errorf("%v used expected to be %v but it's actually %v", test.modes, test.used, actual)
+		}
+
+		if actual := test.modes.String(); actual != test.expected {
+			t.Errorf("%v expected to be %s but it's actually %s", test.modes, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"fmt"
+	"time"
+)
+
+// NB The Mode is 0 when the sentence is in the legacy (pre NMEA 2.3) format.
+type GPGLL struct {
+	BaseSentence
+	Latitude            Optional[float64]
+	Longitude           Optional[float64]
+	CoordinatePrecision int // number of decimal digits of the Latitude and Longitude minutes in the sentence. e.g. 4 for ddmm.mmmm.
+	Time                time.Duration
+	Status              byte // A=data valid; V=data not valid.
+	Mode                byte // A=Autonomous mode; D=Differential mode; E=Estimated mode. N=NULL; F=Float RTK mode; R=RTK mode; M=Manual input mode; S=Simulator mode; P=Precise mode.
+}
+
+// Geographic Position - Latitude/Longitude.
+//
+// A legacy (pre NMEA 2.3) example:
+//
+//	$GPGLL,2307.1256,N,12016.4438,E,064951.000,A*
+//
+// A NMEA 2.3 example:
+//
+//	$GPGLL,2307.1256,N,12016.4438,E,064951.000,A,A*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | Latitude      | 2307.1256  |         | ddmm.mmmm                    |
+// |  1 | N/S indicator | N          |         | N=North or S=South           |
+// |  2 | Longitude     | 12016.4438 |         | dddmm.mmmm                   |
+// |  3 | E/W indicator | E          |         | E=East or W=West             |
+// |  4 | UTC Time      | 064951.000 |         | hhmmss.sss                   |
+// |  5 | Status        | A          |         | A=data valid or              |
+// |    |               |            |         | V=data not valid             |
+// |  6 | Mode          | A          |         | A=Autonomous mode            |
+// |    |               |            |         | D=Differential mode          |
+// |    |               |            |         | E=Estimated mode             |
+// |    |               |            |         | N=NULL                       |
+// |    |               |            |         | F=Float RTK mode (NMEA 4.1)  |
+// |    |               |            |         | R=RTK mode (NMEA 4.1)        |
+// |    |               |            |         | M=Manual input mode          |
+// |    |               |            |         | S=Simulator mode             |
+// |    |               |            |         | P=Precise mode (NMEA 4.1)    |
+// |    |               |            |         | Only in NMEA 2.3 and later   |
+// +----+---------------+------------+---------+------------------------------+
+func parseGPGLL(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &GPGLL{BaseSentence: base}
+
+	if len(fields) != 6 && len(fields) != 7 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	var err error
+
+	//
+	// latitude.  if available. e.g.: 2307.1256  format: ddmm.mmmm
+	// longitude. if available. e.g.: 12016.4438 format: dddmm.mmmm
+	result.Latitude, result.Longitude, result.CoordinatePrecision, err = parsePosition(sentenceType, fields, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	//
+	// time. e.g.: 064951.000 format: hhmmss.sss
+	timeMs, err := parseTime(fields[4])
+	if err != nil {
+		return nil, newFieldError(sentenceType, fields, 4, "UTC Time", err)
+	}
+
+	result.Time = time.Duration(timeMs) * time.Millisecond
+
+	//
+	// status.
+	if fields[5] != "A" && fields[5] != "V" {
+		return nil, newFieldError(sentenceType, fields, 5, "Status", fmt.Errorf("Failed to parse status %s", fields[5]))
+	}
+
+	result.Status = fields[5][0]
+
+	//
+	// mode. only in NMEA 2.3 and later.
+	if len(fields) == 7 {
+		result.Mode, err = parseMode(sentenceType, fields, 6)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (GPGLL) Type() string {
+	return "GLL"
+}
+
+func (s *GPGLL) String() string {
+	return marshalString(s)
+}
+
+func (s *GPGLL) marshalFields(e *Encoder) []string {
+	fields := make([]string, 6)
+
+	fields[0], fields[1], fields[2], fields[3] = e.formatPosition(s.Latitude, s.Longitude, s.CoordinatePrecision)
+
+	fields[4] = e.formatTime(s.Time)
+	fields[5] = formatByte(s.Status)
+
+	if s.Mode != 0 {
+		fields = append(fields, formatByte(s.Mode))
+	}
+
+	return fields
+}
@@ -0,0 +1,126 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+type GPGST struct {
+	BaseSentence
+	Time                 time.Duration
+	RMS                  Optional[float32] // RMS value of the standard deviation of the pseudorange residuals.
+	SemiMajorError       Optional[float32] // in meters. 1-sigma error of the semi-major axis of the error ellipse.
+	SemiMinorError       Optional[float32] // in meters. 1-sigma error of the semi-minor axis of the error ellipse.
+	SemiMajorOrientation Optional[float32] // in degrees. orientation of the semi-major axis of the error ellipse from the true north.
+	LatitudeError        Optional[float32] // in meters. 1-sigma error of the latitude.
+	LongitudeError       Optional[float32] // in meters. 1-sigma error of the longitude.
+	AltitudeError        Optional[float32] // in meters. 1-sigma error of the altitude.
+}
+
+// HorizontalError returns the 1-sigma horizontal position error in meters,
+// that is, the root sum square of the LatitudeError and LongitudeError or,
+// when they are absent, the SemiMajorError.
+func (s *GPGST) HorizontalError() Optional[float32] {
+	if s.LatitudeError.Valid && s.LongitudeError.Valid {
+		return Some(float32(math.Hypot(float64(s.LatitudeError.Value), float64(s.LongitudeError.Value))))
+	}
+	return s.SemiMajorError
+}
+
+// GNSS Pseudorange Error Statistics.
+//
+// Example:
+//
+//	$GPGST,064951.000,2.3,1.7,1.2,35.5,1.5,1.4,3.1*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | UTC Time      | 064951.000 |         | hhmmss.sss                   |
+// |  1 | RMS           | 2.3        |         | RMS value of the standard    |
+// |    |               |            |         | deviation of the pseudorange |
+// |    |               |            |         | residuals                    |
+// |  2 | Semi-major    | 1.7        | meters  | Standard deviation of the    |
+// |    | Error         |            |         | semi-major axis of the error |
+// |    |               |            |         | ellipse                      |
+// |  3 | Semi-minor    | 1.2        | meters  | Standard deviation of the    |
+// |    | Error         |            |         | semi-minor axis of the error |
+// |    |               |            |         | ellipse                      |
+// |  4 | Orientation   | 35.5       | degrees | Orientation of the           |
+// |    |               |            |         | semi-major axis from true    |
+// |    |               |            |         | north                        |
+// |  5 | Latitude      | 1.5        | meters  | Standard deviation of the    |
+// |    | Error         |            |         | latitude error               |
+// |  6 | Longitude     | 1.4        | meters  | Standard deviation of the    |
+// |    | Error         |            |         | longitude error              |
+// |  7 | Altitude      | 3.1        | meters  | Standard deviation of the    |
+// |    | Error         |            |         | altitude error               |
+// +----+---------------+------------+---------+------------------------------+
+func parseGPGST(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &GPGST{BaseSentence: base}
+
+	if len(fields) != 8 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	//
+	// time. e.g.: 064951.000 format: hhmmss.sss
+	timeMs, err := parseTime(fields[0])
+	if err != nil {
+		return nil, newFieldError(sentenceType, fields, 0, "UTC Time", err)
+	}
+
+	result.Time = time.Duration(timeMs) * time.Millisecond
+
+	//
+	// statistics. if available.
+	statistics := []struct {
+		name  string
+		value *Optional[float32]
+	}{
+		{"RMS", &result.RMS},
+		{"Semi-major Error", &result.SemiMajorError},
+		{"Semi-minor Error", &result.SemiMinorError},
+		{"Orientation", &result.SemiMajorOrientation},
+		{"Latitude Error", &result.LatitudeError},
+		{"Longitude Error", &result.LongitudeError},
+		{"Altitude Error", &result.AltitudeError},
+	}
+
+	for i, statistic := range statistics {
+		*statistic.value, err = parseOptionalFloat(sentenceType, fields, i+1, statistic.name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (GPGST) Type() string {
+	return "GST"
+}
+
+func (s *GPGST) String() string {
+	return marshalString(s)
+}
+
+func (s *GPGST) marshalFields(e *Encoder) []string {
+	return []string{
+		e.formatTime(s.Time),
+		e.formatOptionalFloat(s.RMS),
+		e.formatOptionalFloat(s.SemiMajorError),
+		e.formatOptionalFloat(s.SemiMinorError),
+		e.formatOptionalFloat(s.SemiMajorOrientation),
+		e.formatOptionalFloat(s.LatitudeError),
+		e.formatOptionalFloat(s.LongitudeError),
+		e.formatOptionalFloat(s.AltitudeError),
+	}
+}
@@ -0,0 +1,322 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GPGSV is the complete sky view, that is, all the satellites of all the
+// "message N of M" sentences.
+//
+// NB Raw returns all the sentences joined by CRLF.
+type GPGSV struct {
+	BaseSentence
+	SatellitesInView byte
+	SignalID         byte // NMEA 4.10 signal ID. 0=Not available.
+	Satellites       []GSVSatellite
+}
+
+type GSVSatellite struct {
+	PRN       uint16
+	Elevation Optional[byte]   // in degrees. 0 to 90.
+	Azimuth   Optional[uint16] // in degrees. 0 to 359.
+	SNR       Optional[byte]   // in dB-Hz. 0 to 99. absent when not tracking.
+}
+
+// GPGSVMessage is a single "message N of M" GSV sentence. Its Satellites
+// are only the ones of this message.
+type GPGSVMessage struct {
+	TotalMessages byte
+	MessageNumber byte
+	GPGSV
+}
+
+// GNSS Satellites in View.
+//
+// Example:
+//
+//	$GPGSV,3,1,09,29,36,029,42,21,46,314,43,26,44,020,43,15,21,321,39*7D
+//	$GPGSV,3,2,09,18,26,314,40,09,57,170,44,06,20,229,37,10,26,084,37*77
+//	$GPGSV,3,3,09,07,,,26*73
+//
+// Fields:
+//
+// +----+---------------+---------+---------+--------------------------------+
+// |  # | name          | example | units   | description                    |
+// +----+---------------+---------+---------+--------------------------------+
+// |  0 | Number of     | 3       |         | Range 1 to 4                   |
+// |    | Messages      |         |         |                                |
+// |  1 | Message       | 1       |         | Range 1 to 4                   |
+// |    | Number        |         |         |                                |
+// |  2 | Satellites in | 09      |         |                                |
+// |    | View          |         |         |                                |
+// |  3 | Satellite ID  | 29      |         | Channel 1 (Range 1 to 32)      |
+// |  4 | Elevation     | 36      | degrees | Channel 1 (Maximum 90)         |
+// |  5 | Azimuth       | 029     | degrees | Channel 1 (True, Range 0 to    |
+// |    |               |         |         | 359)                           |
+// |  6 | SNR (C/No)    | 42      | dB-Hz   | Range 0 to 99, null when not   |
+// |    |               |         |         | tracking                       |
+// | .. | ...           |         |         | ...                            |
+// | 15 | Satellite ID  | 15      |         | Channel 4 (Range 1 to 32)      |
+// | 16 | Elevation     | 21      | degrees | Channel 4 (Maximum 90)         |
+// | 17 | Azimuth       | 321     | degrees | Channel 4 (True, Range 0 to    |
+// |    |               |         |         | 359)                           |
+// | 18 | SNR (C/No)    | 39      | dB-Hz   | Range 0 to 99, null when not   |
+// |    |               |         |         | tracking                       |
+// | 19 | Signal ID     |         |         | Only in NMEA 4.10              |
+// +----+---------------+---------+---------+--------------------------------+
+//
+// NB The last message can have less than 4 satellites.
+func parseGPGSV(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &GPGSVMessage{}
+	result.BaseSentence = base
+
+	l := len(fields)
+
+	if l < 3 || (l-3)%4 > 1 || l > 3+4*4+1 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, l)
+	}
+
+	//
+	// number of messages.
+	totalMessages, err := strconv.ParseUint(fields[0], 10, 8)
+	if err != nil || totalMessages < 1 {
+		return nil, newFieldError(sentenceType, fields, 0, "Number of Messages", fmt.Errorf("Failed to parse number of messages %s", fields[0]))
+	}
+	result.TotalMessages = byte(totalMessages)
+
+	//
+	// message number.
+	messageNumber, err := strconv.ParseUint(fields[1], 10, 8)
+	if err != nil || messageNumber < 1 || messageNumber > totalMessages {
+		return nil, newFieldError(sentenceType, fields, 1, "Message Number", fmt.Errorf("Failed to parse message number %s", fields[1]))
+	}
+	result.MessageNumber = byte(messageNumber)
+
+	//
+	// satellites in view.
+	satellitesInView, err := strconv.ParseUint(fields[2], 10, 8)
+	if err != nil {
+		return nil, newFieldError(sentenceType, fields, 2, "Satellites in View", err)
+	}
+	result.SatellitesInView = byte(satellitesInView)
+
+	//
+	// signal ID. only in NMEA 4.10.
+	if (l-3)%4 == 1 {
+		signalIDField := fields[l-1]
+		if len(signalIDField) > 0 {
+			signalID, err := strconv.ParseUint(signalIDField, 16, 8)
+			if err != nil {
+				return nil, newFieldError(sentenceType, fields, l-1, "Signal ID", err)
+			}
+			result.SignalID = byte(signalID)
+		}
+		l--
+	}
+
+	//
+	// satellites.
+	satellites := make([]GSVSatellite, 0, (l-3)/4)
+	for i := 3; i < l; i += 4 {
+		// skip the empty channels that some receivers send to pad the sentence.
+		if len(fields[i]) == 0 {
+			continue
+		}
+
+		satellite := GSVSatellite{}
+
+		prn, err := strconv.ParseUint(fields[i], 10, 16)
+		if err != nil {
+			return nil, newFieldError(sentenceType, fields, i, "Satellite ID", err)
+		}
+		satellite.PRN = uint16(prn)
+
+		if len(fields[i+1]) > 0 {
+			elevation, err := strconv.ParseUint(fields[i+1], 10, 8)
+			if err != nil || elevation > 90 {
+				return nil, newFieldError(sentenceType, fields, i+1, "Elevation", fmt.Errorf("Failed to parse elevation %s", fields[i+1]))
+			}
+			satellite.Elevation = Some(byte(elevation))
+		}
+
+		if len(fields[i+2]) > 0 {
+			azimuth, err := strconv.ParseUint(fields[i+2], 10, 16)
+			if err != nil || azimuth > 359 {
+				return nil, newFieldError(sentenceType, fields, i+2, "Azimuth", fmt.Errorf("Failed to parse azimuth %s", fields[i+2]))
+			}
+			satellite.Azimuth = Some(uint16(azimuth))
+		}
+
+		if len(fields[i+3]) > 0 {
+			snr, err := strconv.ParseUint(fields[i+3], 10, 8)
+			if err != nil || snr > 99 {
+				return nil, newFieldError(sentenceType, fields, i+3, "SNR", fmt.Errorf("Failed to parse SNR %s", fields[i+3]))
+			}
+			satellite.SNR = Some(byte(snr))
+		}
+
+		satellites = append(satellites, satellite)
+	}
+	result.Satellites = satellites
+
+	return result, nil
+}
+
+// GPGSVAssembler joins the "message N of M" GSV sentences into a single
+// GPGSV. The zero value is ready to use.
+//
+// The messages can arrive in any order. When a message does not belong to
+// the sky view that is being assembled (e.g. its number was already seen or
+// the number of messages differs) the incomplete sky view is discarded and a
+// new one is started.
+//
+// Each talker (e.g. GP and GL) sends its own sequence. NMEA 4.10 receivers
+// also send one sequence per signal ID. So each talker and signal ID pair is
+// assembled independently.
+type GPGSVAssembler struct {
+	pending map[gpgsvKey]*gpgsvPending
+}
+
+type gpgsvKey struct {
+	talker   TalkerID
+	signalID byte
+}
+
+type gpgsvPending struct {
+	totalMessages    byte
+	satellitesInView byte
+	messages         []*GPGSVMessage // indexed by message number - 1.
+	count            int
+}
+
+// Add adds a message. Returns the complete sky view when all its messages
+// were added, or nil otherwise.
+func (a *GPGSVAssembler) Add(message *GPGSVMessage) *GPGSV {
+	if a.pending == nil {
+		a.pending = make(map[gpgsvKey]*gpgsvPending)
+	}
+
+	key := gpgsvKey{message.Talker, message.SignalID}
+
+	p := a.pending[key]
+
+	if p == nil ||
+		p.totalMessages != message.TotalMessages ||
+		p.satellitesInView != message.SatellitesInView ||
+		p.messages[message.MessageNumber-1] != nil {
+		p = &gpgsvPending{
+			totalMessages:    message.TotalMessages,
+			satellitesInView: message.SatellitesInView,
+			messages:         make([]*GPGSVMessage, message.TotalMessages),
+		}
+		a.pending[key] = p
+	}
+
+	p.messages[message.MessageNumber-1] = message
+	p.count++
+
+	if p.count < int(p.totalMessages) {
+		return nil
+	}
+
+	delete(a.pending, key)
+
+	result := &GPGSV{
+		SatellitesInView: message.SatellitesInView,
+		SignalID:         message.SignalID,
+		Satellites:       make([]GSVSatellite, 0, message.SatellitesInView),
+	}
+
+	sentences := make([]string, len(p.messages))
+
+	for i, m := range p.messages {
+		result.Satellites = append(result.Satellites, m.Satellites...)
+		sentences[i] = m.Text
+	}
+
+	result.BaseSentence = BaseSentence{
+		Talker: message.Talker,
+		Text:   strings.Join(sentences, "\r\n"),
+	}
+
+	return result
+}
+
+func (GPGSV) Type() string {
+	return "GSV"
+}
+
+func (s *GPGSV) String() string {
+	return marshalString(s)
+}
+
+func (s *GPGSVMessage) String() string {
+	return marshalString(s)
+}
+
+func (s *GPGSVMessage) marshalFields(e *Encoder) []string {
+	fields := []string{
+		strconv.Itoa(int(s.TotalMessages)),
+		strconv.Itoa(int(s.MessageNumber)),
+		fmt.Sprintf("%02d", s.SatellitesInView),
+	}
+
+	for _, satellite := range s.Satellites {
+		fields = append(
+			fields,
+			fmt.Sprintf("%02d", satellite.PRN),
+			formatOptionalInt(satellite.Elevation, 2),
+			formatOptionalInt(satellite.Azimuth, 3),
+			formatOptionalInt(satellite.SNR, 2))
+	}
+
+	if s.SignalID != 0 {
+		fields = append(fields, strconv.FormatUint(uint64(s.SignalID), 16))
+	}
+
+	return fields
+}
+
+// splits the sky view into its "message N of M" sentences, each with up to 4
+// satellites.
+func (e *Encoder) marshalGPGSV(s *GPGSV) (string, error) {
+	totalMessages := (len(s.Satellites) + 3) / 4
+	if totalMessages == 0 {
+		totalMessages = 1
+	}
+
+	sentences := make([]string, totalMessages)
+
+	for i := range sentences {
+		satellites := s.Satellites[i*4:]
+		if len(satellites) > 4 {
+			satellites = satellites[:4]
+		}
+
+		message := &GPGSVMessage{
+			TotalMessages: byte(totalMessages),
+			MessageNumber: byte(i + 1),
+			GPGSV: GPGSV{
+				BaseSentence:     s.BaseSentence,
+				SatellitesInView: s.SatellitesInView,
+				SignalID:         s.SignalID,
+				Satellites:       satellites,
+			},
+		}
+
+		sentence, err := e.Marshal(message)
+		if err != nil {
+			return "", err
+		}
+
+		sentences[i] = sentence
+	}
+
+	return strings.Join(sentences, "\r\n"), nil
+}
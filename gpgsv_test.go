@@ -0,0 +1,105 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type gpgsvVisitor struct {
+	visitor
+	results []*GPGSV
+}
+
+func (v *gpgsvVisitor) OnGPGSV(gpgsv *GPGSV) {
+	v.results = append(v.results, gpgsv)
+}
+
+var (
+	gpgsv1 = "$GPGSV,3,1,09,29,36,029,42,21,46,314,43,26,44,020,43,15,21,321,39*7D"
+	gpgsv2 = "$GPGSV,3,2,09,18,26,314,40,09,57,170,44,06,20,229,37,10,26,084,37*77"
+	gpgsv3 = "$GPGSV,3,3,09,07,,,26*73"
+)
+
+var gpgsvSkyView = &GPGSV{
+	BaseSentence:     BaseSentence{Talker: TalkerGPS, Text: gpgsv1 + "\r\n" + gpgsv2 + "\r\n" + gpgsv3},
+	SatellitesInView: 9,
+	Satellites: []GSVSatellite{
+		{PRN: 29, Elevation: Some[byte](36), Azimuth: Some[uint16](29), SNR: Some[byte](42)},
+		{PRN: 21, Elevation: Some[byte](46), Azimuth: Some[uint16](314), SNR: Some[byte](43)},
+		{PRN: 26, Elevation: Some[byte](44), Azimuth: Some[uint16](20), SNR: Some[byte](43)},
+		{PRN: 15, Elevation: Some[byte](21), Azimuth: Some[uint16](321), SNR: Some[byte](39)},
+		{PRN: 18, Elevation: Some[byte](26), Azimuth: Some[uint16](314), SNR: Some[byte](40)},
+		{PRN: 9, Elevation: Some[byte](57), Azimuth: Some[uint16](170), SNR: Some[byte](44)},
+		{PRN: 6, Elevation: Some[byte](20), Azimuth: Some[uint16](229), SNR: Some[byte](37)},
+		{PRN: 10, Elevation: Some[byte](26), Azimuth: Some[uint16](84), SNR: Some[byte](37)},
+		{PRN: 7, SNR: Some[byte](26)}}}
+
+func TestGPGSVAssembly(t *testing.T) {
+	tests := []struct {
+		name      string
+		sentences []string
+		expected  []*GPGSV
+	}{
+		{"in order", []string{gpgsv1, gpgsv2, gpgsv3}, []*GPGSV{gpgsvSkyView}},
+		{"out of order", []string{gpgsv2, gpgsv3, gpgsv1}, []*GPGSV{gpgsvSkyView}},
+		{"missing message", []string{gpgsv1, gpgsv3, gpgsv1, gpgsv2, gpgsv3}, []*GPGSV{gpgsvSkyView}},
+		{"incomplete", []string{gpgsv1, gpgsv2}, nil},
+		{"repeated", []string{gpgsv1, gpgsv2, gpgsv3, gpgsv3, gpgsv1, gpgsv2}, []*GPGSV{gpgsvSkyView, gpgsvSkyView}},
+	}
+
+	for _, test := range tests {
+		visitor := &gpgsvVisitor{}
+
+		err := Visit(strings.NewReader(strings.Join(test.sentences, "\r\n")), visitor)
+		if err != nil {
+			t.Errorf("%s: unexpected error `%v`", test.name, err)
+		}
+
+		if !reflect.DeepEqual(test.expected, visitor.results) {
+			t.Errorf("%s: expected `%v` but got `%v`", test.name, test.expected, visitor.results)
+		}
+	}
+}
+
+func TestGPGSVAssemblyByTalkerAndSignalID(t *testing.T) {
+	sentences := []string{
+		"$GLGSV,1,1,01,70,36,029,42,1*",
+		"$GPGSV,2,1,02,29,36,029,42,1*",
+		"$GPGSV,1,1,01,29,36,029,38,8*",
+		"$GPGSV,2,2,02,21,46,314,43,1*",
+	}
+
+	for i, sentence := range sentences {
+		sentences[i] = sentence + checksum(sentence)
+	}
+
+	visitor := &gpgsvVisitor{}
+
+	Visit(strings.NewReader(strings.Join(sentences, "\n")), visitor)
+
+	expected := []*GPGSV{
+		&GPGSV{
+			BaseSentence:     BaseSentence{Talker: TalkerGLONASS, Text: sentences[0]},
+			SatellitesInView: 1,
+			SignalID:         1,
+			Satellites:       []GSVSatellite{{PRN: 70, Elevation: Some[byte](36), Azimuth: Some[uint16](29), SNR: Some[byte](42)}}},
+		&GPGSV{
+			BaseSentence:     BaseSentence{Talker: TalkerGPS, Text: sentences[2]},
+			SatellitesInView: 1,
+			SignalID:         8,
+			Satellites:       []GSVSatellite{{PRN: 29, Elevation: Some[byte](36), Azimuth: Some[uint16](29), SNR: Some[byte](38)}}},
+		&GPGSV{
+			BaseSentence:     BaseSentence{Talker: TalkerGPS, Text: sentences[1] + "\r\n" + sentences[3]},
+			SatellitesInView: 2,
+			SignalID:         1,
+			Satellites: []GSVSatellite{
+				{PRN: 29, Elevation: Some[byte](36), Azimuth: Some[uint16](29), SNR: Some[byte](42)},
+				{PRN: 21, Elevation: Some[byte](46), Azimuth: Some[uint16](314), SNR: Some[byte](43)}}}}
+
+	if !reflect.DeepEqual(expected, visitor.results) {
+		t.Errorf("expected `%v` but got `%v`", expected, visitor.results)
+	}
+}
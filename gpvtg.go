@@ -0,0 +1,161 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// NB The Mode is 0 when the sentence is in the legacy (pre NMEA 2.3) format.
+type GPVTG struct {
+	BaseSentence
+	TrueCourse     Optional[float32] // in degrees.
+	MagneticCourse Optional[float32] // in degrees.
+	SpeedKnots     Optional[float32] // in knots.
+	SpeedKmh       Optional[float32] // in km/h.
+	Mode           byte              // A=Autonomous mode; D=Differential mode; E=Estimated mode. N=NULL; F=Float RTK mode; R=RTK mode; M=Manual input mode; S=Simulator mode; P=Precise mode.
+}
+
+// Course and speed information relative to the ground.
+//
+// A legacy (pre NMEA 2.3) example:
+//
+//	$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*48
+//
+// A NMEA 2.3 example:
+//
+//	$GPVTG,165.48,T,,M,0.03,N,0.06,K,A*36
+//
+// Fields:
+//
+// +----+---------------+---------+---------+--------------------------------+
+// |  # | name          | example | units   | description                    |
+// +----+---------------+---------+---------+--------------------------------+
+// |  0 | Course        | 165.48  | degrees | Measured heading               |
+// |  1 | Reference     | T       |         | True                           |
+// |  2 | Course        |         | degrees | Measured heading (Needs        |
+// |    |               |         |         | GlobalTop Customization        |
+// |    |               |         |         | Service)                       |
+// |  3 | Reference     | M       |         | Magnetic                       |
+// |  4 | Speed         | 0.03    | knots   | Measured speed                 |
+// |  5 | Units         | N       |         | Knots                          |
+// |  6 | Speed         | 0.06    | km/hr   | Measured speed                 |
+// |  7 | Units         | K       |         | Kilometers per hour            |
+// |  8 | Mode          | A       |         | A=Autonomous mode              |
+// |    |               |         |         | D=Differential mode            |
+// |    |               |         |         | E=Estimated mode               |
+// |    |               |         |         | N=NULL                         |
+// |    |               |         |         | F=Float RTK mode (NMEA 4.1)    |
+// |    |               |         |         | R=RTK mode (NMEA 4.1)          |
+// |    |               |         |         | M=Manual input mode            |
+// |    |               |         |         | S=Simulator mode               |
+// |    |               |         |         | P=Precise mode (NMEA 4.1)      |
+// |    |               |         |         | Only in NMEA 2.3 and later     |
+// +----+---------------+---------+---------+--------------------------------+
+func parseGPVTG(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &GPVTG{BaseSentence: base}
+
+	if len(fields) != 8 && len(fields) != 9 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	//
+	// units.
+	units := []struct {
+		index int
+		name  string
+		value string
+	}{
+		{1, "Reference", "T"},
+		{3, "Reference", "M"},
+		{5, "Units", "N"},
+		{7, "Units", "K"},
+	}
+	for _, u := range units {
+		if fields[u.index] != u.value {
+			return nil, newFieldError(sentenceType, fields, u.index, u.name, fmt.Errorf("Failed to parse units %s", fields[u.index]))
+		}
+	}
+
+	//
+	// true course.
+	if len(fields[0]) > 0 {
+		trueCourse, err := strconv.ParseFloat(fields[0], 32)
+		if err != nil {
+			return nil, newFieldError(sentenceType, fields, 0, "True Course", err)
+		}
+		result.TrueCourse = Some(float32(trueCourse))
+	}
+
+	//
+	// magnetic course.
+	if len(fields[2]) > 0 {
+		magneticCourse, err := strconv.ParseFloat(fields[2], 32)
+		if err != nil {
+			return nil, newFieldError(sentenceType, fields, 2, "Magnetic Course", err)
+		}
+		result.MagneticCourse = Some(float32(magneticCourse))
+	}
+
+	//
+	// speed in knots.
+	if len(fields[4]) > 0 {
+		speedKnots, err := strconv.ParseFloat(fields[4], 32)
+		if err != nil {
+			return nil, newFieldError(sentenceType, fields, 4, "Speed Knots", err)
+		}
+		result.SpeedKnots = Some(float32(speedKnots))
+	}
+
+	//
+	// speed in km/h.
+	if len(fields[6]) > 0 {
+		speedKmh, err := strconv.ParseFloat(fields[6], 32)
+		if err != nil {
+			return nil, newFieldError(sentenceType, fields, 6, "Speed Km/h", err)
+		}
+		result.SpeedKmh = Some(float32(speedKmh))
+	}
+
+	//
+	// mode. only in NMEA 2.3 and later.
+	if len(fields) == 9 {
+		mode, err := parseMode(sentenceType, fields, 8)
+		if err != nil {
+			return nil, err
+		}
+		result.Mode = mode
+	}
+
+	return result, nil
+}
+
+func (GPVTG) Type() string {
+	return "VTG"
+}
+
+func (s *GPVTG) String() string {
+	return marshalString(s)
+}
+
+func (s *GPVTG) marshalFields(e *Encoder) []string {
+	fields := []string{
+		e.formatOptionalFloat(s.TrueCourse),
+		"T",
+		e.formatOptionalFloat(s.MagneticCourse),
+		"M",
+		e.formatOptionalFloat(s.SpeedKnots),
+		"N",
+		e.formatOptionalFloat(s.SpeedKmh),
+		"K",
+	}
+
+	if s.Mode != 0 {
+		fields = append(fields, formatByte(s.Mode))
+	}
+
+	return fields
+}
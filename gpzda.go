@@ -0,0 +1,136 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// NB The Location is nil when the sentence has no local zone.
+type GPZDA struct {
+	BaseSentence
+	Time     time.Time      // UTC date and time.
+	Location *time.Location // local zone. e.g. UTC-05:00. use Time.In(Location) to get the local time.
+}
+
+// Time and date.
+//
+// Example:
+//
+//	$GPZDA,064951.00,26,04,2006,-05,00*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | UTC Time      | 064951.00  |         | hhmmss.ss                    |
+// |  1 | Day           | 26         |         | 01 to 31                     |
+// |  2 | Month         | 04         |         | 01 to 12                     |
+// |  3 | Year          | 2006       |         | Four digits                  |
+// |  4 | Local Zone    | -05        | hours   | -13 to 13. Empty when not    |
+// |    | Hours         |            |         | available                    |
+// |  5 | Local Zone    | 00         | minutes | 00 to 59. Same sign as the   |
+// |    | Minutes       |            |         | hours                        |
+// +----+---------------+------------+---------+------------------------------+
+func parseGPZDA(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &GPZDA{BaseSentence: base}
+
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	//
+	// time. e.g.: 064951.00 format: hhmmss.ss
+	timeMs, err := parseTime(fields[0])
+	if err != nil {
+		return nil, newFieldError(sentenceType, fields, 0, "UTC Time", err)
+	}
+
+	//
+	// date. e.g.: 26,04,2006
+	day, err := strconv.ParseUint(fields[1], 10, 8)
+	if err != nil || day < 1 || day > 31 {
+		return nil, newFieldError(sentenceType, fields, 1, "Day", fmt.Errorf("Failed to parse day %s", fields[1]))
+	}
+
+	month, err := strconv.ParseUint(fields[2], 10, 8)
+	if err != nil || month < 1 || month > 12 {
+		return nil, newFieldError(sentenceType, fields, 2, "Month", fmt.Errorf("Failed to parse month %s", fields[2]))
+	}
+
+	year, err := strconv.ParseUint(fields[3], 10, 16)
+	if err != nil || len(fields[3]) != 4 {
+		return nil, newFieldError(sentenceType, fields, 3, "Year", fmt.Errorf("Failed to parse year %s", fields[3]))
+	}
+
+	date, err := dates.Date(int(year), int(month), int(day))
+	if err != nil {
+		return nil, newFieldError(sentenceType, fields, 1, "Day", err)
+	}
+
+	result.Time = date.Add(time.Duration(timeMs) * time.Millisecond)
+
+	//
+	// local zone. if available.
+	if len(fields[4]) > 0 {
+		hours, err := strconv.ParseInt(fields[4], 10, 8)
+		if err != nil || hours < -13 || hours > 13 {
+			return nil, newFieldError(sentenceType, fields, 4, "Local Zone Hours", fmt.Errorf("Failed to parse local zone hours %s", fields[4]))
+		}
+
+		minutes, err := strconv.ParseInt(fields[5], 10, 8)
+		if err != nil || minutes < -59 || minutes > 59 {
+			return nil, newFieldError(sentenceType, fields, 5, "Local Zone Minutes", fmt.Errorf("Failed to parse local zone minutes %s", fields[5]))
+		}
+
+		// the minutes have the sign of the hours, which can be -00.
+		if minutes > 0 && fields[4][0] == '-' {
+			minutes = -minutes
+		}
+
+		result.Location = time.FixedZone("", int(hours)*60*60+int(minutes)*60)
+	}
+
+	return result, nil
+}
+
+func (GPZDA) Type() string {
+	return "ZDA"
+}
+
+func (s *GPZDA) String() string {
+	return marshalString(s)
+}
+
+func (s *GPZDA) marshalFields(e *Encoder) []string {
+	t := s.Time.UTC()
+
+	fields := []string{
+		e.formatTime(timeOfDay(t)),
+		fmt.Sprintf("%02d", t.Day()),
+		fmt.Sprintf("%02d", int(t.Month())),
+		fmt.Sprintf("%04d", t.Year()),
+		"",
+		"",
+	}
+
+	if s.Location != nil {
+		_, offset := t.In(s.Location).Zone()
+
+		sign := ""
+		if offset < 0 {
+			sign = "-"
+			offset = -offset
+		}
+
+		fields[4] = fmt.Sprintf("%s%02d", sign, offset/(60*60))
+		fields[5] = fmt.Sprintf("%02d", offset%(60*60)/60)
+	}
+
+	return fields
+}
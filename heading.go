@@ -0,0 +1,345 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+type HDT struct {
+	BaseSentence
+	Heading Optional[float32] // in degrees. true.
+}
+
+type HDM struct {
+	BaseSentence
+	Heading Optional[float32] // in degrees. magnetic.
+}
+
+type HDG struct {
+	BaseSentence
+	Heading   Optional[float32] // in degrees. magnetic sensor heading.
+	Deviation Optional[float32] // in degrees. east is positive and west is negative.
+	Variation Optional[float32] // in degrees. east is positive and west is negative.
+}
+
+type THS struct {
+	BaseSentence
+	Heading Optional[float32] // in degrees. true.
+	Mode    byte              // A=Autonomous; E=Estimated; M=Manual input; S=Simulator; V=Data not valid.
+}
+
+type ROT struct {
+	BaseSentence
+	RateOfTurn Optional[float32] // in degrees per minute. negative when the bow turns to port.
+	Status     byte              // A=data valid; V=data not valid.
+}
+
+// Heading - True.
+//
+// Example:
+//
+//	$HEHDT,274.07,T*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | Heading       | 274.07     | degrees | True heading                 |
+// |  1 | Reference     | T          |         | True                         |
+// +----+---------------+------------+---------+------------------------------+
+func parseHDT(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	heading, err := parseHeading(sentenceType, fields, 0, "T")
+	if err != nil {
+		return nil, err
+	}
+
+	return &HDT{BaseSentence: base, Heading: heading}, nil
+}
+
+// Heading - Magnetic.
+//
+// Example:
+//
+//	$HCHDM,238.5,M*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | Heading       | 238.5      | degrees | Magnetic heading             |
+// |  1 | Reference     | M          |         | Magnetic                     |
+// +----+---------------+------------+---------+------------------------------+
+func parseHDM(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	heading, err := parseHeading(sentenceType, fields, 0, "M")
+	if err != nil {
+		return nil, err
+	}
+
+	return &HDM{BaseSentence: base, Heading: heading}, nil
+}
+
+// Heading, Deviation and Variation.
+//
+// Example:
+//
+//	$HCHDG,98.3,0.0,E,12.6,W*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | Heading       | 98.3       | degrees | Magnetic sensor heading      |
+// |  1 | Deviation     | 0.0        | degrees | Magnetic deviation           |
+// |  2 | E/W indicator | E          |         | E=East or W=West             |
+// |  3 | Variation     | 12.6       | degrees | Magnetic variation           |
+// |  4 | E/W indicator | W          |         | E=East or W=West             |
+// +----+---------------+------------+---------+------------------------------+
+func parseHDG(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &HDG{BaseSentence: base}
+
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	var err error
+
+	//
+	// heading.
+	result.Heading, err = parseHeading(sentenceType, fields, 0, "")
+	if err != nil {
+		return nil, err
+	}
+
+	//
+	// deviation.
+	result.Deviation, err = parseMagneticAngle(sentenceType, fields, 1, "Deviation")
+	if err != nil {
+		return nil, err
+	}
+
+	//
+	// variation.
+	result.Variation, err = parseMagneticAngle(sentenceType, fields, 3, "Variation")
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// True Heading and Status.
+//
+// Example:
+//
+//	$GPTHS,77.52,E*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | Heading       | 77.52      | degrees | True heading                 |
+// |  1 | Mode          | E          |         | A=Autonomous                 |
+// |    |               |            |         | E=Estimated (dead reckoning) |
+// |    |               |            |         | M=Manual input               |
+// |    |               |            |         | S=Simulator                  |
+// |    |               |            |         | V=Data not valid             |
+// +----+---------------+------------+---------+------------------------------+
+func parseTHS(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	heading, err := parseHeading(sentenceType, fields, 0, "")
+	if err != nil {
+		return nil, err
+	}
+
+	switch fields[1] {
+	case "A", "E", "M", "S", "V":
+	default:
+		return nil, newFieldError(sentenceType, fields, 1, "Mode", fmt.Errorf("Failed to parse mode %s", fields[1]))
+	}
+
+	return &THS{BaseSentence: base, Heading: heading, Mode: fields[1][0]}, nil
+}
+
+// Rate Of Turn.
+//
+// Example:
+//
+//	$HEROT,-3.4,A*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | Rate of Turn  | -3.4       | deg/min | Negative when the bow turns  |
+// |    |               |            |         | to port                      |
+// |  1 | Status        | A          |         | A=data valid or              |
+// |    |               |            |         | V=data not valid             |
+// +----+---------------+------------+---------+------------------------------+
+func parseROT(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	rateOfTurn, err := parseOptionalFloat(sentenceType, fields, 0, "Rate of Turn")
+	if err != nil {
+		return nil, err
+	}
+
+	if fields[1] != "A" && fields[1] != "V" {
+		return nil, newFieldError(sentenceType, fields, 1, "Status", fmt.Errorf("Failed to parse status %s", fields[1]))
+	}
+
+	return &ROT{BaseSentence: base, RateOfTurn: rateOfTurn, Status: fields[1][0]}, nil
+}
+
+// parses an optional heading field, in degrees, from 0 to 360. when reference
+// is not empty, the next field must be the reference, e.g. T for true or M
+// for magnetic, but it can be empty when the heading is empty.
+func parseHeading(sentenceType string, fields []string, index int, reference string) (Optional[float32], error) {
+	if reference != "" && fields[index+1] != reference && (len(fields[index]) > 0 || len(fields[index+1]) > 0) {
+		return Optional[float32]{}, newFieldError(sentenceType, fields, index+1, "Reference", fmt.Errorf("Failed to parse reference %s", fields[index+1]))
+	}
+
+	heading, err := parseOptionalFloat(sentenceType, fields, index, "Heading")
+	if err != nil {
+		return Optional[float32]{}, err
+	}
+
+	if heading.Valid && (heading.Value < 0 || heading.Value > 360) {
+		return Optional[float32]{}, newFieldError(sentenceType, fields, index, "Heading", fmt.Errorf("Failed to parse heading %s", fields[index]))
+	}
+
+	return heading, nil
+}
+
+// parses an optional angle field followed by its E/W indicator field. west
+// is negative.
+func parseMagneticAngle(sentenceType string, fields []string, index int, name string) (Optional[float32], error) {
+	if len(fields[index]) == 0 {
+		return Optional[float32]{}, nil
+	}
+
+	angle, err := strconv.ParseFloat(fields[index], 32)
+	if err != nil {
+		return Optional[float32]{}, newFieldError(sentenceType, fields, index, name, err)
+	}
+
+	switch fields[index+1] {
+	case "E":
+	case "W":
+		angle *= -1
+	default:
+		return Optional[float32]{}, newFieldError(sentenceType, fields, index+1, name+" E/W indicator", fmt.Errorf("Failed to parse indicator %s", fields[index+1]))
+	}
+
+	return Some(float32(angle)), nil
+}
+
+// returns the angle and its E/W indicator, or empty strings when it's absent.
+func (e *Encoder) formatMagneticAngle(angle Optional[float32]) (string, string) {
+	if !angle.Valid {
+		return "", ""
+	}
+
+	indicator := "E"
+	if angle.Value < 0 {
+		indicator = "W"
+	}
+
+	return e.formatFloat(float32(math.Abs(float64(angle.Value)))), indicator
+}
+
+func (HDT) Type() string {
+	return "HDT"
+}
+
+func (s *HDT) String() string {
+	return marshalString(s)
+}
+
+func (s *HDT) marshalFields(e *Encoder) []string {
+	return []string{e.formatOptionalFloat(s.Heading), "T"}
+}
+
+func (HDM) Type() string {
+	return "HDM"
+}
+
+func (s *HDM) String() string {
+	return marshalString(s)
+}
+
+func (s *HDM) marshalFields(e *Encoder) []string {
+	return []string{e.formatOptionalFloat(s.Heading), "M"}
+}
+
+func (HDG) Type() string {
+	return "HDG"
+}
+
+func (s *HDG) String() string {
+	return marshalString(s)
+}
+
+func (s *HDG) marshalFields(e *Encoder) []string {
+	fields := make([]string, 5)
+
+	fields[0] = e.formatOptionalFloat(s.Heading)
+	fields[1], fields[2] = e.formatMagneticAngle(s.Deviation)
+	fields[3], fields[4] = e.formatMagneticAngle(s.Variation)
+
+	return fields
+}
+
+func (THS) Type() string {
+	return "THS"
+}
+
+func (s *THS) String() string {
+	return marshalString(s)
+}
+
+func (s *THS) marshalFields(e *Encoder) []string {
+	return []string{e.formatOptionalFloat(s.Heading), formatByte(s.Mode)}
+}
+
+func (ROT) Type() string {
+	return "ROT"
+}
+
+func (s *ROT) String() string {
+	return marshalString(s)
+}
+
+func (s *ROT) marshalFields(e *Encoder) []string {
+	return []string{e.formatOptionalFloat(s.RateOfTurn), formatByte(s.Status)}
+}
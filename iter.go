@@ -0,0 +1,56 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"bufio"
+	"io"
+	"iter"
+)
+
+// Sentences returns an iterator over the sentences read from reader, e.g.:
+//
+//	for s, err := range nmea.Sentences(f) {
+//		if err != nil {
+//			continue
+//		}
+//		switch s := s.(type) {
+//		case *nmea.GPGGA:
+//			...
+//		}
+//	}
+//
+// The sentences are validated and parsed like in Visit, including joining the
+// "message N of M" GPGSV and RTE sentences into a single *GPGSV and *RTE, and
+// the AIS fragments into a single *VDM. A sentence that cannot be parsed is
+// yielded as a nil Sentence and its error. The last yielded value is the
+// reader error, if any, except when the reader was closed.
+func Sentences(reader io.Reader, options ...ParseOption) iter.Seq2[Sentence, error] {
+	return func(yield func(Sentence, error) bool) {
+		scanner := bufio.NewScanner(reader)
+
+		parser := &streamParser{options: newParseOptions(options)}
+
+		for scanner.Scan() {
+			sentence := scanner.Text()
+
+			if len(sentence) == 0 {
+				continue
+			}
+
+			s, err := parser.parse(sentence)
+
+			if s == nil && err == nil {
+				continue
+			}
+
+			if !yield(s, err) {
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil && !isClosedError(err) {
+			yield(nil, err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestSentences(t *testing.T) {
+	text := strings.Join([]string{
+		"$GPGGA,064951.000,2307.1256,N,12016.4438,E,1,8,0.95,39.9,M,17.8,M,,*63",
+		"",
+		gpgsv1,
+		"$GPGGA,064951.000,,,,,0,0,,,M,,M,,*48",
+		gpgsv2,
+		gpgsv3,
+		"$GPGSA,A,3,03,04,01,32,22,28,11,,,,,,2.32,0.95,2.11*02",
+	}, "\r\n")
+
+	var types []string
+	var errs []error
+
+	for s, err := range Sentences(strings.NewReader(text)) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		types = append(types, s.Type())
+	}
+
+	if strings.Join(types, ",") != "GGA,GSV,GSA" {
+		t.Errorf("expected the GGA,GSV,GSA sentences but got %v", types)
+	}
+
+	var checksumError *ChecksumError
+	if len(errs) != 1 || !errors.As(errs[0], &checksumError) {
+		t.Errorf("expected a single ChecksumError but got %v", errs)
+	}
+}
+
+func TestSentencesBreak(t *testing.T) {
+	reader := &countingReader{Reader: strings.NewReader(strings.Repeat("$GPGGA,064951.123,,,,,0,0,,,M,,M,,*47\r\n", 1000))}
+
+	count := 0
+
+	for range Sentences(iotest.OneByteReader(reader)) {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+
+	if count != 2 {
+		t.Errorf("expected 2 sentences but got %d", count)
+	}
+
+	// the scanner buffers some data, but it must not read the whole reader.
+	if reader.reads >= 1000 {
+		t.Errorf("expected the reader to stop being read after the break, but it was read %d times", reader.reads)
+	}
+}
+
+func TestSentencesReaderError(t *testing.T) {
+	expected := errors.New("device disconnected")
+
+	var errs []error
+
+	for _, err := range Sentences(io.MultiReader(strings.NewReader(gpgsv3+"\r\n"), iotest.ErrReader(expected))) {
+		errs = append(errs, err)
+	}
+
+	if len(errs) != 1 || errs[0] != expected {
+		t.Errorf("expected `%v` but got %v", expected, errs)
+	}
+}
+
+type countingReader struct {
+	io.Reader
+	reads int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	r.reads++
+	return r.Reader.Read(p)
+}
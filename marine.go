@@ -0,0 +1,606 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import "fmt"
+
+// SpeedUnit is the unit of a speed field whose unit varies, e.g. the MWV wind
+// speed.
+type SpeedUnit byte
+
+const (
+	SpeedKnots           SpeedUnit = 'N'
+	SpeedKmh             SpeedUnit = 'K' // kilometers per hour.
+	SpeedMetersPerSecond SpeedUnit = 'M'
+	SpeedMph             SpeedUnit = 'S' // statute miles per hour.
+)
+
+func (u SpeedUnit) String() string {
+	switch u {
+	case SpeedKnots:
+		return "knots"
+	case SpeedKmh:
+		return "km/h"
+	case SpeedMetersPerSecond:
+		return "m/s"
+	case SpeedMph:
+		return "mph"
+	}
+	return fmt.Sprintf("SpeedUnit(%d)", byte(u))
+}
+
+type DBT struct {
+	BaseSentence
+	DepthFeet    Optional[float32]
+	DepthMeters  Optional[float32]
+	DepthFathoms Optional[float32]
+}
+
+type DPT struct {
+	BaseSentence
+	DepthMeters        Optional[float32] // relative to the transducer.
+	OffsetMeters       Optional[float32] // from the transducer. positive to the waterline and negative to the keel.
+	MaximumRangeMeters Optional[float32] // maximum range scale in use. absent before NMEA 3.0.
+}
+
+type MTW struct {
+	BaseSentence
+	TemperatureCelsius Optional[float32]
+}
+
+type VHW struct {
+	BaseSentence
+	TrueHeading     Optional[float32] // in degrees.
+	MagneticHeading Optional[float32] // in degrees.
+	SpeedKnots      Optional[float32] // speed through water.
+	SpeedKmh        Optional[float32] // speed through water.
+}
+
+type VLW struct {
+	BaseSentence
+	TotalWaterDistanceNM  Optional[float32] // in nautical miles.
+	WaterDistanceNM       Optional[float32] // in nautical miles. since reset.
+	TotalGroundDistanceNM Optional[float32] // in nautical miles. absent before NMEA 3.0.
+	GroundDistanceNM      Optional[float32] // in nautical miles. since reset. absent before NMEA 3.0.
+}
+
+type MWV struct {
+	BaseSentence
+	WindAngle Optional[float32] // in degrees. clockwise from the bow.
+	Reference byte              // R=Relative (apparent); T=Theoretical (true).
+	WindSpeed Optional[float32] // in SpeedUnit.
+	SpeedUnit SpeedUnit
+	Status    byte // A=data valid; V=data not valid.
+}
+
+type MWD struct {
+	BaseSentence
+	TrueDirection        Optional[float32] // in degrees. direction the wind blows from.
+	MagneticDirection    Optional[float32] // in degrees. direction the wind blows from.
+	SpeedKnots           Optional[float32]
+	SpeedMetersPerSecond Optional[float32]
+}
+
+// Depth Below Transducer.
+//
+// Example:
+//
+//	$SDDBT,36.1,f,11.0,M,6.0,F*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | Depth         | 36.1       | feet    |                              |
+// |  1 | Units         | f          |         | Feet                         |
+// |  2 | Depth         | 11.0       | meters  |                              |
+// |  3 | Units         | M          |         | Meters                       |
+// |  4 | Depth         | 6.0        | fathoms |                              |
+// |  5 | Units         | F          |         | Fathoms                      |
+// +----+---------------+------------+---------+------------------------------+
+func parseDBT(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &DBT{BaseSentence: base}
+
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	err := parseUnitFloats(sentenceType, fields, []unitFloat{
+		{0, "Depth", "f", &result.DepthFeet},
+		{2, "Depth", "M", &result.DepthMeters},
+		{4, "Depth", "F", &result.DepthFathoms},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Depth.
+//
+// Example:
+//
+//	$SDDPT,11.0,-0.5,100*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | Depth         | 11.0       | meters  | Relative to the transducer   |
+// |  1 | Offset        | -0.5       | meters  | From the transducer.         |
+// |    |               |            |         | Positive to the waterline,   |
+// |    |               |            |         | negative to the keel         |
+// |  2 | Maximum Range | 100        | meters  | Only in NMEA 3.0 and later   |
+// |    | Scale         |            |         |                              |
+// +----+---------------+------------+---------+------------------------------+
+func parseDPT(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &DPT{BaseSentence: base}
+
+	if len(fields) != 2 && len(fields) != 3 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	var err error
+
+	result.DepthMeters, err = parseOptionalFloat(sentenceType, fields, 0, "Depth")
+	if err != nil {
+		return nil, err
+	}
+
+	result.OffsetMeters, err = parseOptionalFloat(sentenceType, fields, 1, "Offset")
+	if err != nil {
+		return nil, err
+	}
+
+	//
+	// maximum range scale. only in NMEA 3.0 and later.
+	if len(fields) == 3 {
+		result.MaximumRangeMeters, err = parseOptionalFloat(sentenceType, fields, 2, "Maximum Range Scale")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// Mean Temperature of Water.
+//
+// Example:
+//
+//	$YXMTW,17.75,C*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | Temperature   | 17.75      | celsius |                              |
+// |  1 | Units         | C          |         | Celsius                      |
+// +----+---------------+------------+---------+------------------------------+
+func parseMTW(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &MTW{BaseSentence: base}
+
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	err := parseUnitFloats(sentenceType, fields, []unitFloat{
+		{0, "Temperature", "C", &result.TemperatureCelsius},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Water Speed and Heading.
+//
+// Example:
+//
+//	$VWVHW,245.1,T,245.1,M,5.2,N,9.6,K*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | Heading       | 245.1      | degrees | True heading                 |
+// |  1 | Reference     | T          |         | True                         |
+// |  2 | Heading       | 245.1      | degrees | Magnetic heading             |
+// |  3 | Reference     | M          |         | Magnetic                     |
+// |  4 | Speed         | 5.2        | knots   | Speed through water          |
+// |  5 | Units         | N          |         | Knots                        |
+// |  6 | Speed         | 9.6        | km/hr   | Speed through water          |
+// |  7 | Units         | K          |         | Kilometers per hour          |
+// +----+---------------+------------+---------+------------------------------+
+func parseVHW(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &VHW{BaseSentence: base}
+
+	if len(fields) != 8 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	var err error
+
+	result.TrueHeading, err = parseHeading(sentenceType, fields, 0, "T")
+	if err != nil {
+		return nil, err
+	}
+
+	result.MagneticHeading, err = parseHeading(sentenceType, fields, 2, "M")
+	if err != nil {
+		return nil, err
+	}
+
+	err = parseUnitFloats(sentenceType, fields, []unitFloat{
+		{4, "Speed", "N", &result.SpeedKnots},
+		{6, "Speed", "K", &result.SpeedKmh},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Distance Traveled through Water.
+//
+// Example:
+//
+//	$VWVLW,7803.2,N,0.0,N,7802.5,N,0.0,N*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | Total Water   | 7803.2     | NM      | Total cumulative distance    |
+// |    | Distance      |            |         | through water                |
+// |  1 | Units         | N          |         | Nautical miles               |
+// |  2 | Water         | 0.0        | NM      | Distance through water since |
+// |    | Distance      |            |         | reset                        |
+// |  3 | Units         | N          |         | Nautical miles               |
+// |  4 | Total Ground  | 7802.5     | NM      | Total cumulative distance    |
+// |    | Distance      |            |         | over ground. Only in NMEA    |
+// |    |               |            |         | 3.0 and later                |
+// |  5 | Units         | N          |         | Nautical miles               |
+// |  6 | Ground        | 0.0        | NM      | Distance over ground since   |
+// |    | Distance      |            |         | reset. Only in NMEA 3.0 and  |
+// |    |               |            |         | later                        |
+// |  7 | Units         | N          |         | Nautical miles               |
+// +----+---------------+------------+---------+------------------------------+
+func parseVLW(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &VLW{BaseSentence: base}
+
+	if len(fields) != 4 && len(fields) != 8 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	distances := []unitFloat{
+		{0, "Total Water Distance", "N", &result.TotalWaterDistanceNM},
+		{2, "Water Distance", "N", &result.WaterDistanceNM},
+	}
+
+	//
+	// ground distances. only in NMEA 3.0 and later.
+	if len(fields) == 8 {
+		distances = append(
+			distances,
+			unitFloat{4, "Total Ground Distance", "N", &result.TotalGroundDistanceNM},
+			unitFloat{6, "Ground Distance", "N", &result.GroundDistanceNM})
+	}
+
+	if err := parseUnitFloats(sentenceType, fields, distances); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Wind Speed and Angle.
+//
+// Example:
+//
+//	$WIMWV,214.8,R,0.1,K,A*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | Wind Angle    | 214.8      | degrees | 0 to 359, clockwise from the |
+// |    |               |            |         | bow                          |
+// |  1 | Reference     | R          |         | R=Relative (apparent)        |
+// |    |               |            |         | T=Theoretical (true)         |
+// |  2 | Wind Speed    | 0.1        |         |                              |
+// |  3 | Units         | K          |         | K=km/h                       |
+// |    |               |            |         | M=m/s                        |
+// |    |               |            |         | N=knots                      |
+// |    |               |            |         | S=statute mph                |
+// |  4 | Status        | A          |         | A=data valid or              |
+// |    |               |            |         | V=data not valid             |
+// +----+---------------+------------+---------+------------------------------+
+func parseMWV(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &MWV{BaseSentence: base}
+
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	var err error
+
+	//
+	// wind angle.
+	result.WindAngle, err = parseHeading(sentenceType, fields, 0, "")
+	if err != nil {
+		return nil, err
+	}
+
+	//
+	// reference.
+	if fields[1] != "R" && fields[1] != "T" {
+		return nil, newFieldError(sentenceType, fields, 1, "Reference", fmt.Errorf("Failed to parse reference %s", fields[1]))
+	}
+	result.Reference = fields[1][0]
+
+	//
+	// wind speed.
+	result.WindSpeed, err = parseOptionalFloat(sentenceType, fields, 2, "Wind Speed")
+	if err != nil {
+		return nil, err
+	}
+
+	switch fields[3] {
+	case "K", "M", "N", "S":
+		result.SpeedUnit = SpeedUnit(fields[3][0])
+	default:
+		return nil, newFieldError(sentenceType, fields, 3, "Units", fmt.Errorf("Failed to parse units %s", fields[3]))
+	}
+
+	//
+	// status.
+	if fields[4] != "A" && fields[4] != "V" {
+		return nil, newFieldError(sentenceType, fields, 4, "Status", fmt.Errorf("Failed to parse status %s", fields[4]))
+	}
+	result.Status = fields[4][0]
+
+	return result, nil
+}
+
+// Wind Direction and Speed.
+//
+// Example:
+//
+//	$WIMWD,10.1,T,10.1,M,12,N,40,M*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | Direction     | 10.1       | degrees | True wind direction          |
+// |  1 | Reference     | T          |         | True                         |
+// |  2 | Direction     | 10.1       | degrees | Magnetic wind direction      |
+// |  3 | Reference     | M          |         | Magnetic                     |
+// |  4 | Speed         | 12         | knots   | Wind speed                   |
+// |  5 | Units         | N          |         | Knots                        |
+// |  6 | Speed         | 40         | m/s     | Wind speed                   |
+// |  7 | Units         | M          |         | Meters per second            |
+// +----+---------------+------------+---------+------------------------------+
+func parseMWD(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &MWD{BaseSentence: base}
+
+	if len(fields) != 8 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	var err error
+
+	result.TrueDirection, err = parseHeading(sentenceType, fields, 0, "T")
+	if err != nil {
+		return nil, err
+	}
+
+	result.MagneticDirection, err = parseHeading(sentenceType, fields, 2, "M")
+	if err != nil {
+		return nil, err
+	}
+
+	err = parseUnitFloats(sentenceType, fields, []unitFloat{
+		{4, "Speed", "N", &result.SpeedKnots},
+		{6, "Speed", "M", &result.SpeedMetersPerSecond},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// an optional decimal field followed by its units field.
+type unitFloat struct {
+	index int
+	name  string
+	units string
+	value *Optional[float32]
+}
+
+// parses the optional decimal fields. the units field must have the expected
+// units, or be empty when the value is empty.
+func parseUnitFloats(sentenceType string, fields []string, values []unitFloat) error {
+	for _, v := range values {
+		unitsField := fields[v.index+1]
+
+		if unitsField != v.units && (unitsField != "" || fields[v.index] != "") {
+			return newFieldError(sentenceType, fields, v.index+1, "Units", fmt.Errorf("Failed to parse units %s", unitsField))
+		}
+
+		value, err := parseOptionalFloat(sentenceType, fields, v.index, v.name)
+		if err != nil {
+			return err
+		}
+
+		*v.value = value
+	}
+
+	return nil
+}
+
+func (DBT) Type() string {
+	return "DBT"
+}
+
+func (s *DBT) String() string {
+	return marshalString(s)
+}
+
+func (s *DBT) marshalFields(e *Encoder) []string {
+	return []string{
+		e.formatOptionalFloat(s.DepthFeet),
+		"f",
+		e.formatOptionalFloat(s.DepthMeters),
+		"M",
+		e.formatOptionalFloat(s.DepthFathoms),
+		"F",
+	}
+}
+
+func (DPT) Type() string {
+	return "DPT"
+}
+
+func (s *DPT) String() string {
+	return marshalString(s)
+}
+
+func (s *DPT) marshalFields(e *Encoder) []string {
+	fields := []string{
+		e.formatOptionalFloat(s.DepthMeters),
+		e.formatOptionalFloat(s.OffsetMeters),
+	}
+
+	if s.MaximumRangeMeters.Valid {
+		fields = append(fields, e.formatOptionalFloat(s.MaximumRangeMeters))
+	}
+
+	return fields
+}
+
+func (MTW) Type() string {
+	return "MTW"
+}
+
+func (s *MTW) String() string {
+	return marshalString(s)
+}
+
+func (s *MTW) marshalFields(e *Encoder) []string {
+	return []string{e.formatOptionalFloat(s.TemperatureCelsius), "C"}
+}
+
+func (VHW) Type() string {
+	return "VHW"
+}
+
+func (s *VHW) String() string {
+	return marshalString(s)
+}
+
+func (s *VHW) marshalFields(e *Encoder) []string {
+	return []string{
+		e.formatOptionalFloat(s.TrueHeading),
+		"T",
+		e.formatOptionalFloat(s.MagneticHeading),
+		"M",
+		e.formatOptionalFloat(s.SpeedKnots),
+		"N",
+		e.formatOptionalFloat(s.SpeedKmh),
+		"K",
+	}
+}
+
+func (VLW) Type() string {
+	return "VLW"
+}
+
+func (s *VLW) String() string {
+	return marshalString(s)
+}
+
+func (s *VLW) marshalFields(e *Encoder) []string {
+	fields := []string{
+		e.formatOptionalFloat(s.TotalWaterDistanceNM),
+		"N",
+		e.formatOptionalFloat(s.WaterDistanceNM),
+		"N",
+	}
+
+	if s.TotalGroundDistanceNM.Valid || s.GroundDistanceNM.Valid {
+		fields = append(
+			fields,
+			e.formatOptionalFloat(s.TotalGroundDistanceNM),
+			"N",
+			e.formatOptionalFloat(s.GroundDistanceNM),
+			"N")
+	}
+
+	return fields
+}
+
+func (MWV) Type() string {
+	return "MWV"
+}
+
+func (s *MWV) String() string {
+	return marshalString(s)
+}
+
+func (s *MWV) marshalFields(e *Encoder) []string {
+	return []string{
+		e.formatOptionalFloat(s.WindAngle),
+		formatByte(s.Reference),
+		e.formatOptionalFloat(s.WindSpeed),
+		formatByte(byte(s.SpeedUnit)),
+		formatByte(s.Status),
+	}
+}
+
+func (MWD) Type() string {
+	return "MWD"
+}
+
+func (s *MWD) String() string {
+	return marshalString(s)
+}
+
+func (s *MWD) marshalFields(e *Encoder) []string {
+	return []string{
+		e.formatOptionalFloat(s.TrueDirection),
+		"T",
+		e.formatOptionalFloat(s.MagneticDirection),
+		"M",
+		e.formatOptionalFloat(s.SpeedKnots),
+		"N",
+		e.formatOptionalFloat(s.SpeedMetersPerSecond),
+		"M",
+	}
+}
@@ -0,0 +1,242 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Encoder turns the parsed sentences back into NMEA sentences.
+//
+// NB The zero value Encoder has no decimal digits, i.e. it outputs the times
+// as hhmmss, the coordinates as ddmm and the other decimal fields rounded to
+// integers. Use DefaultEncoder, or set all the fields, to keep the decimals.
+type Encoder struct {
+	CoordinatePrecision int // number of decimal digits of the latitude and longitude minutes. 0 to 9. e.g. 4 for ddmm.mmmm. -1 uses the CoordinatePrecision of the sentence, or 4 when it's 0.
+	TimePrecision       int // number of decimal digits of the time seconds. 0 to 9. e.g. 3 for hhmmss.sss. the other digits are truncated.
+	Precision           int // number of decimal digits of the other decimal fields. 0 to 9. -1 uses the smallest number of digits that represents the value exactly.
+
+	lastMessageID atomic.Uint32 // the last sequential message ID assigned to a multi-fragment VDM.
+}
+
+// the maximum number of decimal digits of the Encoder precisions, i.e. of
+// nanoseconds.
+const maxPrecision = 9
+
+// the maximum number of characters between the $ and the * of a sentence,
+// i.e. 82 minus the $, the *, the checksum and the CRLF.
+const maxSentenceDataLength = 82 - 1 - 3 - 2
+
+// DefaultEncoder is used by Marshal and by the String method of the sentences.
+var DefaultEncoder = &Encoder{
+	CoordinatePrecision: -1,
+	TimePrecision:       3,
+	Precision:           -1,
+}
+
+// implemented by the sentences that can be marshaled.
+type marshaler interface {
+	marshalFields(e *Encoder) []string
+}
+
+// Marshal marshals the sentence with the DefaultEncoder.
+func Marshal(s Sentence) (string, error) {
+	return DefaultEncoder.Marshal(s)
+}
+
+// Marshal returns the sentence in the NMEA format, including the checksum,
+// but without the CRLF line terminator. e.g. $GPGGA,...*63.
+//
+// When the sentence has no talker ID, the GP talker ID is used.
+//
+// A *GPGSV is marshaled into all its "message N of M" sentences joined by
+// CRLF. Likewise, a *RTE is marshaled into as many "message N of M"
+// sentences as needed to keep each sentence within 82 characters, and a *VDM
+// into as many "fragment N of M" sentences as needed for its Payload. A
+// multi-fragment *VDM without a MessageID is assigned the next sequential
+// message ID of the Encoder.
+//
+// The VDM and VDO sentences start with ! and, when they have no talker ID,
+// the AI talker ID is used.
+//
+// Returns an error when a precision of the Encoder is out of range.
+func (e *Encoder) Marshal(s Sentence) (string, error) {
+	if e.CoordinatePrecision < -1 || e.CoordinatePrecision > maxPrecision {
+		return "", fmt.Errorf("Failed to marshal %T: CoordinatePrecision %d is not -1 or 0 to %d", s, e.CoordinatePrecision, maxPrecision)
+	}
+
+	if e.TimePrecision < 0 || e.TimePrecision > maxPrecision {
+		return "", fmt.Errorf("Failed to marshal %T: TimePrecision %d is not 0 to %d", s, e.TimePrecision, maxPrecision)
+	}
+
+	if e.Precision < -1 || e.Precision > maxPrecision {
+		return "", fmt.Errorf("Failed to marshal %T: Precision %d is not -1 or 0 to %d", s, e.Precision, maxPrecision)
+	}
+
+	if gpgsv, ok := s.(*GPGSV); ok {
+		return e.marshalGPGSV(gpgsv)
+	}
+
+	if rte, ok := s.(*RTE); ok {
+		return e.marshalRTE(rte)
+	}
+
+	if vdm, ok := s.(*VDM); ok {
+		return e.marshalVDM(vdm)
+	}
+
+	m, ok := s.(marshaler)
+	if !ok {
+		return "", fmt.Errorf("Failed to marshal %T: not supported", s)
+	}
+
+	talker := s.TalkerID()
+	delimiter := "$"
+
+	if _, ok := s.(*VDMFragment); ok {
+		delimiter = "!"
+		if talker == "" {
+			talker = TalkerAIS
+		}
+	}
+
+	if talker == "" {
+		talker = TalkerGPS
+	}
+
+	data := string(talker) + s.Type() + "," + strings.Join(m.marshalFields(e), ",")
+
+	return fmt.Sprintf("%s%s*%02X", delimiter, data, computeChecksum(data)), nil
+}
+
+// returns the sentence or an error description, as expected from a String
+// method.
+func marshalString(s Sentence) string {
+	sentence, err := Marshal(s)
+	if err != nil {
+		return fmt.Sprintf("%%!(NMEA=%v)", err)
+	}
+	return sentence
+}
+
+// time of day. e.g.: 6h49m51s output: 064951.000 format: hhmmss.sss
+func (e *Encoder) formatTime(d time.Duration) string {
+	unit := time.Duration(math.Pow10(9 - e.TimePrecision))
+
+	// truncate instead of rounding, so that, e.g., 23:59:59.9996 is output as
+	// 235959.999 instead of 240000.000.
+	d = d.Truncate(unit)
+
+	h := d / time.Hour
+	m := d % time.Hour / time.Minute
+	s := d % time.Minute / time.Second
+
+	text := fmt.Sprintf("%02d%02d%02d", h, m, s)
+
+	if e.TimePrecision > 0 {
+		text += fmt.Sprintf(".%0*d", e.TimePrecision, d%time.Second/unit)
+	}
+
+	return text
+}
+
+// date. e.g.: 2006-04-26 output: 260406 format: ddmmyy
+func formatDate(t time.Time) string {
+	return fmt.Sprintf("%02d%02d%02d", t.Day(), t.Month(), t.Year()%100)
+}
+
+// returns the time of day of t.
+func timeOfDay(t time.Time) time.Duration {
+	return t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()))
+}
+
+// returns the latitude, N/S indicator, longitude and E/W indicator fields, or
+// empty strings when the position is absent.
+func (e *Encoder) formatPosition(latitude, longitude Optional[float64], precision int) (string, string, string, string) {
+	if !latitude.Valid || !longitude.Valid {
+		return "", "", "", ""
+	}
+
+	latitudeField, latitudeIndicator := e.formatLatitude(latitude.Value, precision)
+	longitudeField, longitudeIndicator := e.formatLongitude(longitude.Value, precision)
+
+	return latitudeField, latitudeIndicator, longitudeField, longitudeIndicator
+}
+
+// latitude. e.g.: input: 23.11876 output: 2307.1256 N format: ddmm.mmmm
+// precision is the CoordinatePrecision of the sentence.
+func (e *Encoder) formatLatitude(latitude float64, precision int) (string, string) {
+	indicator := "N"
+	if latitude < 0 {
+		indicator = "S"
+	}
+	return e.formatCoordinate(latitude, 2, precision), indicator
+}
+
+// longitude. e.g.: input: 120.274063333333334 output: 12016.4438 E format: dddmm.mmmm
+// precision is the CoordinatePrecision of the sentence.
+func (e *Encoder) formatLongitude(longitude float64, precision int) (string, string) {
+	indicator := "E"
+	if longitude < 0 {
+		indicator = "W"
+	}
+	return e.formatCoordinate(longitude, 3, precision), indicator
+}
+
+func (e *Encoder) formatCoordinate(value float64, degreesDigits int, precision int) string {
+	if e.CoordinatePrecision >= 0 {
+		precision = e.CoordinatePrecision
+	} else if precision <= 0 {
+		precision = 4
+	}
+
+	scale := math.Pow10(precision)
+
+	// round the minutes before splitting them from the degrees, so that, e.g.,
+	// 59.99999 minutes carry into the degrees instead of being output as 60.
+	minutes := math.Round(math.Abs(value)*60*scale) / scale
+
+	degrees := math.Floor(minutes / 60)
+
+	minutes -= degrees * 60
+
+	minutesWidth := 2
+	if precision > 0 {
+		minutesWidth += 1 + precision
+	}
+
+	return fmt.Sprintf("%0*d%0*.*f", degreesDigits, int(degrees), minutesWidth, precision, minutes)
+}
+
+func (e *Encoder) formatFloat(value float32) string {
+	return strconv.FormatFloat(float64(value), 'f', e.Precision, 32)
+}
+
+// returns the value, or an empty string when it's absent.
+func (e *Encoder) formatOptionalFloat(value Optional[float32]) string {
+	if !value.Valid {
+		return ""
+	}
+	return e.formatFloat(value.Value)
+}
+
+// returns the zero padded value, or an empty string when it's absent.
+func formatOptionalInt[T byte | uint16](value Optional[T], width int) string {
+	if !value.Valid {
+		return ""
+	}
+	return fmt.Sprintf("%0*d", width, value.Value)
+}
+
+// returns the byte as a string, or an empty string when it's 0.
+func formatByte(value byte) string {
+	if value == 0 {
+		return ""
+	}
+	return string(value)
+}
@@ -0,0 +1,220 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		encoder  *Encoder
+		sentence Sentence
+		expected string
+	}{
+		{
+			DefaultEncoder,
+			&GPGGA{
+				BaseSentence:   BaseSentence{Talker: TalkerGPS},
+				Time:           duration("6h49m51s"),
+				UsedSatellites: 8,
+				PositionFix:    1,
+				Latitude:       Some(23.11876),
+				Longitude:      Some(120.274063333333334),
+				HDOP:           Some[float32](0.95),
+				Altitude:       Some[float32](39.9)},
+			"$GPGGA,064951.000,2307.1256,N,12016.4438,E,1,8,0.95,39.9,M,,M,,*"},
+		{
+			DefaultEncoder,
+			&GPGGA{
+				Time:            duration("6h49m51s"),
+				UsedSatellites:  12,
+				PositionFix:     FixFloatRTK,
+				Latitude:        Some(23.11876),
+				Longitude:       Some(120.274063333333334),
+				HDOP:            Some[float32](0.6),
+				Altitude:        Some[float32](39.9),
+				GeoidSeparation: Some[float32](17.8),
+				DGPSAge:         Some[float32](1.2),
+				DGPSStationID:   Some[uint16](31)},
+			"$GPGGA,064951.000,2307.1256,N,12016.4438,E,5,12,0.6,39.9,M,17.8,M,1.2,0031*"},
+		{
+			DefaultEncoder,
+			&GPGGA{
+				Time: duration("6h49m51s123ms")},
+			"$GPGGA,064951.123,,,,,0,0,,,M,,M,,*"},
+		{
+			// the time is truncated instead of carried into the next day.
+			DefaultEncoder,
+			&GPGGA{
+				Time: duration("23h59m59s999ms600us")},
+			"$GPGGA,235959.999,,,,,0,0,,,M,,M,,*"},
+		{
+			// the zero value has no decimal digits.
+			&Encoder{},
+			&GPGGA{
+				Time:      duration("6h49m51s999ms"),
+				Latitude:  Some(23.11876),
+				Longitude: Some(120.274063333333334),
+				HDOP:      Some[float32](0.95)},
+			"$GPGGA,064951,2307,N,12016,E,0,0,1,,M,,M,,*"},
+		{
+			&Encoder{CoordinatePrecision: 6, TimePrecision: 2, Precision: 2},
+			&GPRMC{
+				BaseSentence: BaseSentence{Talker: TalkerGNSS},
+				Time:         time.Date(2006, 4, 26, 6, 49, 51, 120000000, time.UTC),
+				Status:       'A',
+				Latitude:     Some(-23.11876),
+				Longitude:    Some(-120.274063333333334),
+				Mode:         'D',
+				Speed:        Some[float32](0.03),
+				Heading:      Some[float32](165.48)},
+			"$GNRMC,064951.12,A,2307.125600,S,12016.443800,W,0.03,165.48,260406,,,D*"},
+		{
+			DefaultEncoder,
+			&GPRMC{
+				Time:               time.Date(2006, 4, 26, 6, 49, 51, 0, time.UTC),
+				Status:             'A',
+				Latitude:           Some(23.11876),
+				Longitude:          Some(120.274063333333334),
+				Mode:               'R',
+				Speed:              Some[float32](0.03),
+				Heading:            Some[float32](165.48),
+				MagneticVariation:  Some[float32](-3.05),
+				NavigationalStatus: 'S'},
+			"$GPRMC,064951.000,A,2307.1256,N,12016.4438,E,0.03,165.48,260406,3.05,W,R,S*"},
+		{
+			// the minutes carry into the degrees.
+			&Encoder{CoordinatePrecision: 2, TimePrecision: 0, Precision: -1},
+			&GPRMC{
+				Time:      time.Date(2006, 4, 26, 6, 49, 51, 0, time.UTC),
+				Status:    'A',
+				Latitude:  Some(23.99999999),
+				Longitude: Some(9.5),
+				Mode:      'A'},
+			"$GPRMC,064951,A,2400.00,N,00930.00,E,,,260406,,,A*"},
+		{
+			// the source precision is reproduced.
+			DefaultEncoder,
+			&GPRMC{
+				Time:                time.Date(2006, 4, 26, 6, 49, 51, 0, time.UTC),
+				Status:              'A',
+				Latitude:            Some(coordinate(23, 7.1256789)),
+				Longitude:           Some(coordinate(120, 16.4438123)),
+				CoordinatePrecision: 7,
+				Mode:                'A'},
+			"$GPRMC,064951.000,A,2307.1256789,N,12016.4438123,E,,,260406,,,A*"},
+		{
+			DefaultEncoder,
+			&GPGSA{
+				Mode1: 'A',
+				Mode2: '3',
+				SVs:   []byte{3, 4, 1, 32, 22, 28, 11},
+				PDOP:  Some[float32](2.32),
+				HDOP:  Some[float32](0.95),
+				VDOP:  Some[float32](2.11)},
+			"$GPGSA,A,3,03,04,01,32,22,28,11,,,,,,2.32,0.95,2.11*"},
+		{
+			DefaultEncoder,
+			&GPVTG{
+				TrueCourse: Some[float32](165.48),
+				SpeedKnots: Some[float32](0.03),
+				SpeedKmh:   Some[float32](0.06),
+				Mode:       'A'},
+			"$GPVTG,165.48,T,,M,0.03,N,0.06,K,A*"},
+	}
+
+	for _, test := range tests {
+		expected := test.expected + checksum(test.expected)
+
+		actual, err := test.encoder.Marshal(test.sentence)
+		if err != nil {
+			t.Errorf("`%s` unexpected error `%v`", expected, err)
+		}
+
+		if actual != expected {
+			t.Errorf("expected `%s` but it's actually `%s`", expected, actual)
+		}
+	}
+}
+
+func TestMarshalPrecisionErrors(t *testing.T) {
+	encoders := []*Encoder{
+		{CoordinatePrecision: -1, TimePrecision: 12, Precision: -1},
+		{CoordinatePrecision: -1, TimePrecision: -1, Precision: -1},
+		{CoordinatePrecision: 10, TimePrecision: 3, Precision: -1},
+		{CoordinatePrecision: -2, TimePrecision: 3, Precision: -1},
+		{CoordinatePrecision: -1, TimePrecision: 3, Precision: 10},
+		{CoordinatePrecision: -1, TimePrecision: 3, Precision: -2},
+	}
+
+	for _, e := range encoders {
+		actual, err := e.Marshal(&GPGGA{Time: duration("6h49m51s123ms")})
+		if err == nil {
+			t.Errorf("`%+v` expected an error but got `%s`", e, actual)
+		}
+	}
+
+	// the limits.
+	actual, err := (&Encoder{CoordinatePrecision: 9, TimePrecision: 9, Precision: 9}).Marshal(&GPGGA{Time: duration("6h49m51s123456789ns")})
+	if err != nil {
+		t.Fatalf("unexpected error `%v`", err)
+	}
+
+	if expected := "$GPGGA,064951.123456789,,,,,0,0,,,M,,M,,*" + checksum("$GPGGA,064951.123456789,,,,,0,0,,,M,,M,,*"); actual != expected {
+		t.Errorf("expected `%s` but it's actually `%s`", expected, actual)
+	}
+}
+
+func TestMarshalGPGSV(t *testing.T) {
+	actual, err := Marshal(gpgsvSkyView)
+	if err != nil {
+		t.Fatalf("unexpected error `%v`", err)
+	}
+
+	expected := strings.Join([]string{
+		"$GPGSV,3,1,09,29,36,029,42,21,46,314,43,26,44,020,43,15,21,321,39*7D",
+		"$GPGSV,3,2,09,18,26,314,40,09,57,170,44,06,20,229,37,10,26,084,37*77",
+		// the unknown elevation and azimuth are marshaled as empty fields.
+		"$GPGSV,3,3,09,07,,,26*73"}, "\r\n")
+
+	if actual != expected {
+		t.Errorf("expected `%s` but it's actually `%s`", expected, actual)
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	visitor := &visitor{}
+
+	for _, v := range validSentences {
+		sentence := v.sentence
+
+		if strings.HasSuffix(sentence, "*") {
+			sentence += checksum(sentence)
+		}
+
+		parsed, _ := visitor.visit(sentence)
+
+		marshaled, err := Marshal(parsed.(Sentence))
+		if err != nil {
+			t.Errorf("`%s` unexpected error `%v`", sentence, err)
+			continue
+		}
+
+		actual, _ := visitor.visit(marshaled)
+
+		expected := withRaw(v.expected, marshaled)
+
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf(
+				"`%s` marshaled as `%s` expected to be `%#v` but it's actually `%#v`",
+				sentence,
+				marshaled,
+				expected,
+				actual)
+		}
+	}
+}
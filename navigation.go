@@ -0,0 +1,720 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"fmt"
+	"time"
+)
+
+type RMB struct {
+	BaseSentence
+	Status                byte              // A=data valid; V=navigation receiver warning.
+	CrossTrackError       Optional[float32] // in nautical miles.
+	SteerDirection        byte              // L=Left; R=Right. direction to steer to correct the cross track error.
+	OriginWaypointID      string
+	DestinationWaypointID string
+	DestinationLatitude   Optional[float64]
+	DestinationLongitude  Optional[float64]
+	CoordinatePrecision   int               // number of decimal digits of the DestinationLatitude and DestinationLongitude minutes in the sentence.
+	RangeNM               Optional[float32] // in nautical miles. range to the destination.
+	Bearing               Optional[float32] // in degrees. true bearing to the destination.
+	ClosingVelocity       Optional[float32] // in knots. velocity towards the destination.
+	Arrived               bool              // whether the arrival circle was entered or the perpendicular passed.
+	Mode                  byte              // A=Autonomous mode; D=Differential mode; E=Estimated mode. N=NULL; ... 0 before NMEA 2.3.
+}
+
+type APB struct {
+	BaseSentence
+	Status1                     byte              // A=data valid; V=Loran-C blink or SNR warning.
+	Status2                     byte              // A=data valid; V=Loran-C cycle lock warning.
+	CrossTrackError             Optional[float32] // in CrossTrackUnits.
+	SteerDirection              byte              // L=Left; R=Right.
+	CrossTrackUnits             byte              // N=Nautical miles; K=Kilometers.
+	ArrivalCircleEntered        bool
+	PerpendicularPassed         bool
+	OriginBearing               Optional[float32] // in degrees. from the origin to the destination.
+	OriginBearingReference      byte              // M=Magnetic; T=True.
+	DestinationWaypointID       string
+	DestinationBearing          Optional[float32] // in degrees. from the present position to the destination.
+	DestinationBearingReference byte              // M=Magnetic; T=True.
+	HeadingToSteer              Optional[float32] // in degrees.
+	HeadingToSteerReference     byte              // M=Magnetic; T=True.
+	Mode                        byte              // A=Autonomous mode; D=Differential mode; E=Estimated mode. N=NULL; ... 0 before NMEA 2.3.
+}
+
+type XTE struct {
+	BaseSentence
+	Status1         byte              // A=data valid; V=Loran-C blink or SNR warning.
+	Status2         byte              // A=data valid; V=Loran-C cycle lock warning.
+	CrossTrackError Optional[float32] // in CrossTrackUnits.
+	SteerDirection  byte              // L=Left; R=Right.
+	CrossTrackUnits byte              // N=Nautical miles; K=Kilometers.
+	Mode            byte              // A=Autonomous mode; D=Differential mode; E=Estimated mode. N=NULL; ... 0 before NMEA 2.3.
+}
+
+type BOD struct {
+	BaseSentence
+	TrueBearing           Optional[float32] // in degrees.
+	MagneticBearing       Optional[float32] // in degrees.
+	DestinationWaypointID string
+	OriginWaypointID      string
+}
+
+type BWC struct {
+	BaseSentence
+	Time                time.Duration
+	WaypointLatitude    Optional[float64]
+	WaypointLongitude   Optional[float64]
+	CoordinatePrecision int               // number of decimal digits of the WaypointLatitude and WaypointLongitude minutes in the sentence.
+	TrueBearing         Optional[float32] // in degrees.
+	MagneticBearing     Optional[float32] // in degrees.
+	DistanceNM          Optional[float32] // in nautical miles.
+	WaypointID          string
+	Mode                byte // A=Autonomous mode; D=Differential mode; E=Estimated mode. N=NULL; ... 0 before NMEA 2.3.
+}
+
+type WPL struct {
+	BaseSentence
+	Latitude            Optional[float64]
+	Longitude           Optional[float64]
+	CoordinatePrecision int // number of decimal digits of the Latitude and Longitude minutes in the sentence.
+	WaypointID          string
+}
+
+// Recommended Minimum Navigation Information.
+//
+// Example:
+//
+//	$GPRMB,A,0.66,L,003,004,4917.24,N,12309.57,W,001.3,052.5,000.5,V,A*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | Status        | A          |         | A=data valid or              |
+// |    |               |            |         | V=navigation receiver warning|
+// |  1 | Cross Track   | 0.66       | NM      | Maximum 9.99                 |
+// |    | Error         |            |         |                              |
+// |  2 | Direction to  | L          |         | L=Left or R=Right            |
+// |    | Steer         |            |         |                              |
+// |  3 | Origin        | 003        |         |                              |
+// |    | Waypoint ID   |            |         |                              |
+// |  4 | Destination   | 004        |         |                              |
+// |    | Waypoint ID   |            |         |                              |
+// |  5 | Latitude      | 4917.24    |         | ddmm.mmmm                    |
+// |  6 | N/S indicator | N          |         | N=North or S=South           |
+// |  7 | Longitude     | 12309.57   |         | dddmm.mmmm                   |
+// |  8 | E/W indicator | W          |         | E=East or W=West             |
+// |  9 | Range         | 001.3      | NM      | Range to destination         |
+// | 10 | Bearing       | 052.5      | degrees | True bearing to destination  |
+// | 11 | Closing       | 000.5      | knots   | Velocity towards destination |
+// |    | Velocity      |            |         |                              |
+// | 12 | Arrival       | V          |         | A=Arrival circle entered or  |
+// |    | Status        |            |         | perpendicular passed         |
+// |    |               |            |         | V=Not arrived                |
+// | 13 | Mode          | A          |         | Only in NMEA 2.3 and later   |
+// +----+---------------+------------+---------+------------------------------+
+func parseRMB(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &RMB{BaseSentence: base}
+
+	if len(fields) != 13 && len(fields) != 14 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	var err error
+
+	result.Status, err = parseStatus(sentenceType, fields, 0, "Status")
+	if err != nil {
+		return nil, err
+	}
+
+	result.CrossTrackError, err = parseOptionalFloat(sentenceType, fields, 1, "Cross Track Error")
+	if err != nil {
+		return nil, err
+	}
+
+	result.SteerDirection, err = parseSteerDirection(sentenceType, fields, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	result.OriginWaypointID = fields[3]
+	result.DestinationWaypointID = fields[4]
+
+	result.DestinationLatitude, result.DestinationLongitude, result.CoordinatePrecision, err = parsePosition(sentenceType, fields, 5)
+	if err != nil {
+		return nil, err
+	}
+
+	result.RangeNM, err = parseOptionalFloat(sentenceType, fields, 9, "Range")
+	if err != nil {
+		return nil, err
+	}
+
+	result.Bearing, err = parseHeading(sentenceType, fields, 10, "")
+	if err != nil {
+		return nil, err
+	}
+
+	result.ClosingVelocity, err = parseOptionalFloat(sentenceType, fields, 11, "Closing Velocity")
+	if err != nil {
+		return nil, err
+	}
+
+	arrivalStatus, err := parseStatus(sentenceType, fields, 12, "Arrival Status")
+	if err != nil {
+		return nil, err
+	}
+	result.Arrived = arrivalStatus == 'A'
+
+	if len(fields) == 14 {
+		result.Mode, err = parseMode(sentenceType, fields, 13)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// Heading/Track Controller (Autopilot) Sentence "B".
+//
+// Example:
+//
+//	$GPAPB,A,A,0.10,R,N,V,V,011,M,DEST,011,M,011,M,A*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | Status        | A          |         | A=data valid or V=Loran-C    |
+// |    |               |            |         | blink or SNR warning         |
+// |  1 | Status        | A          |         | A=data valid or V=Loran-C    |
+// |    |               |            |         | cycle lock warning           |
+// |  2 | Cross Track   | 0.10       |         |                              |
+// |    | Error         |            |         |                              |
+// |  3 | Direction to  | R          |         | L=Left or R=Right            |
+// |    | Steer         |            |         |                              |
+// |  4 | Units         | N          |         | N=Nautical miles or          |
+// |    |               |            |         | K=Kilometers                 |
+// |  5 | Arrival       | V          |         | A=Arrival circle entered     |
+// |    | Circle        |            |         |                              |
+// |  6 | Perpendicular | V          |         | A=Perpendicular passed at    |
+// |    |               |            |         | the waypoint                 |
+// |  7 | Bearing       | 011        | degrees | Origin to destination        |
+// |  8 | Reference     | M          |         | M=Magnetic or T=True         |
+// |  9 | Destination   | DEST       |         |                              |
+// |    | Waypoint ID   |            |         |                              |
+// | 10 | Bearing       | 011        | degrees | Present position to          |
+// |    |               |            |         | destination                  |
+// | 11 | Reference     | M          |         | M=Magnetic or T=True         |
+// | 12 | Heading to    | 011        | degrees |                              |
+// |    | Steer         |            |         |                              |
+// | 13 | Reference     | M          |         | M=Magnetic or T=True         |
+// | 14 | Mode          | A          |         | Only in NMEA 2.3 and later   |
+// +----+---------------+------------+---------+------------------------------+
+func parseAPB(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &APB{BaseSentence: base}
+
+	if len(fields) != 14 && len(fields) != 15 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	var err error
+
+	result.Status1, result.Status2, result.CrossTrackError, result.SteerDirection, result.CrossTrackUnits, err = parseCrossTrack(sentenceType, fields, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	arrivalCircle, err := parseStatus(sentenceType, fields, 5, "Arrival Circle")
+	if err != nil {
+		return nil, err
+	}
+	result.ArrivalCircleEntered = arrivalCircle == 'A'
+
+	perpendicular, err := parseStatus(sentenceType, fields, 6, "Perpendicular")
+	if err != nil {
+		return nil, err
+	}
+	result.PerpendicularPassed = perpendicular == 'A'
+
+	result.OriginBearing, result.OriginBearingReference, err = parseBearing(sentenceType, fields, 7)
+	if err != nil {
+		return nil, err
+	}
+
+	result.DestinationWaypointID = fields[9]
+
+	result.DestinationBearing, result.DestinationBearingReference, err = parseBearing(sentenceType, fields, 10)
+	if err != nil {
+		return nil, err
+	}
+
+	result.HeadingToSteer, result.HeadingToSteerReference, err = parseBearing(sentenceType, fields, 12)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) == 15 {
+		result.Mode, err = parseMode(sentenceType, fields, 14)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// Cross-Track Error, Measured.
+//
+// Example:
+//
+//	$GPXTE,A,A,0.67,L,N,A*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | Status        | A          |         | A=data valid or V=Loran-C    |
+// |    |               |            |         | blink or SNR warning         |
+// |  1 | Status        | A          |         | A=data valid or V=Loran-C    |
+// |    |               |            |         | cycle lock warning           |
+// |  2 | Cross Track   | 0.67       |         |                              |
+// |    | Error         |            |         |                              |
+// |  3 | Direction to  | L          |         | L=Left or R=Right            |
+// |    | Steer         |            |         |                              |
+// |  4 | Units         | N          |         | N=Nautical miles or          |
+// |    |               |            |         | K=Kilometers                 |
+// |  5 | Mode          | A          |         | Only in NMEA 2.3 and later   |
+// +----+---------------+------------+---------+------------------------------+
+func parseXTE(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &XTE{BaseSentence: base}
+
+	if len(fields) != 5 && len(fields) != 6 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	var err error
+
+	result.Status1, result.Status2, result.CrossTrackError, result.SteerDirection, result.CrossTrackUnits, err = parseCrossTrack(sentenceType, fields, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) == 6 {
+		result.Mode, err = parseMode(sentenceType, fields, 5)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// Bearing - Origin to Destination.
+//
+// Example:
+//
+//	$GPBOD,099.3,T,105.6,M,POINTB,POINTA*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | Bearing       | 099.3      | degrees | True bearing                 |
+// |  1 | Reference     | T          |         | True                         |
+// |  2 | Bearing       | 105.6      | degrees | Magnetic bearing             |
+// |  3 | Reference     | M          |         | Magnetic                     |
+// |  4 | Destination   | POINTB     |         |                              |
+// |    | Waypoint ID   |            |         |                              |
+// |  5 | Origin        | POINTA     |         | Empty when navigating from   |
+// |    | Waypoint ID   |            |         | the present position         |
+// +----+---------------+------------+---------+------------------------------+
+func parseBOD(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &BOD{BaseSentence: base}
+
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	var err error
+
+	result.TrueBearing, err = parseHeading(sentenceType, fields, 0, "T")
+	if err != nil {
+		return nil, err
+	}
+
+	result.MagneticBearing, err = parseHeading(sentenceType, fields, 2, "M")
+	if err != nil {
+		return nil, err
+	}
+
+	result.DestinationWaypointID = fields[4]
+	result.OriginWaypointID = fields[5]
+
+	return result, nil
+}
+
+// Bearing and Distance to Waypoint - Great Circle.
+//
+// Example:
+//
+//	$GPBWC,220516,5130.02,N,00046.34,W,213.8,T,218.0,M,0004.6,N,EGLM,A*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | UTC Time      | 220516     |         | hhmmss.sss                   |
+// |  1 | Latitude      | 5130.02    |         | Waypoint. ddmm.mmmm          |
+// |  2 | N/S indicator | N          |         | N=North or S=South           |
+// |  3 | Longitude     | 00046.34   |         | Waypoint. dddmm.mmmm         |
+// |  4 | E/W indicator | W          |         | E=East or W=West             |
+// |  5 | Bearing       | 213.8      | degrees | True bearing                 |
+// |  6 | Reference     | T          |         | True                         |
+// |  7 | Bearing       | 218.0      | degrees | Magnetic bearing             |
+// |  8 | Reference     | M          |         | Magnetic                     |
+// |  9 | Distance      | 0004.6     | NM      | Distance to waypoint         |
+// | 10 | Units         | N          |         | Nautical miles               |
+// | 11 | Waypoint ID   | EGLM       |         |                              |
+// | 12 | Mode          | A          |         | Only in NMEA 2.3 and later   |
+// +----+---------------+------------+---------+------------------------------+
+func parseBWC(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &BWC{BaseSentence: base}
+
+	if len(fields) != 12 && len(fields) != 13 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	//
+	// time. e.g.: 220516.000 format: hhmmss.sss
+	timeMs, err := parseTime(fields[0])
+	if err != nil {
+		return nil, newFieldError(sentenceType, fields, 0, "UTC Time", err)
+	}
+
+	result.Time = time.Duration(timeMs) * time.Millisecond
+
+	result.WaypointLatitude, result.WaypointLongitude, result.CoordinatePrecision, err = parsePosition(sentenceType, fields, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	result.TrueBearing, err = parseHeading(sentenceType, fields, 5, "T")
+	if err != nil {
+		return nil, err
+	}
+
+	result.MagneticBearing, err = parseHeading(sentenceType, fields, 7, "M")
+	if err != nil {
+		return nil, err
+	}
+
+	err = parseUnitFloats(sentenceType, fields, []unitFloat{
+		{9, "Distance", "N", &result.DistanceNM},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.WaypointID = fields[11]
+
+	if len(fields) == 13 {
+		result.Mode, err = parseMode(sentenceType, fields, 12)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// Waypoint Location.
+//
+// Example:
+//
+//	$GPWPL,4917.16,N,12310.64,W,003*
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | Latitude      | 4917.16    |         | ddmm.mmmm                    |
+// |  1 | N/S indicator | N          |         | N=North or S=South           |
+// |  2 | Longitude     | 12310.64   |         | dddmm.mmmm                   |
+// |  3 | E/W indicator | W          |         | E=East or W=West             |
+// |  4 | Waypoint ID   | 003        |         |                              |
+// +----+---------------+------------+---------+------------------------------+
+func parseWPL(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &WPL{BaseSentence: base}
+
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	var err error
+
+	result.Latitude, result.Longitude, result.CoordinatePrecision, err = parsePosition(sentenceType, fields, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	result.WaypointID = fields[4]
+
+	return result, nil
+}
+
+// parses an A (data valid or yes) or V (data not valid or no) field.
+func parseStatus(sentenceType string, fields []string, index int, name string) (byte, error) {
+	if fields[index] != "A" && fields[index] != "V" {
+		return 0, newFieldError(sentenceType, fields, index, name, fmt.Errorf("Failed to parse status %s", fields[index]))
+	}
+	return fields[index][0], nil
+}
+
+// parses an optional L (left) or R (right) direction to steer field.
+func parseSteerDirection(sentenceType string, fields []string, index int) (byte, error) {
+	switch fields[index] {
+	case "":
+		return 0, nil
+	case "L", "R":
+		return fields[index][0], nil
+	}
+	return 0, newFieldError(sentenceType, fields, index, "Direction to Steer", fmt.Errorf("Failed to parse direction to steer %s", fields[index]))
+}
+
+// parses an optional bearing field followed by its M (magnetic) or T (true)
+// reference field.
+func parseBearing(sentenceType string, fields []string, index int) (Optional[float32], byte, error) {
+	bearing, err := parseHeading(sentenceType, fields, index, "")
+	if err != nil {
+		return Optional[float32]{}, 0, err
+	}
+
+	switch fields[index+1] {
+	case "":
+		if bearing.Valid {
+			break
+		}
+		return bearing, 0, nil
+	case "M", "T":
+		return bearing, fields[index+1][0], nil
+	}
+
+	return Optional[float32]{}, 0, newFieldError(sentenceType, fields, index+1, "Reference", fmt.Errorf("Failed to parse reference %s", fields[index+1]))
+}
+
+// parses the two status, cross track error, direction to steer and units
+// fields of APB and XTE that start at index.
+func parseCrossTrack(sentenceType string, fields []string, index int) (status1, status2 byte, crossTrackError Optional[float32], steerDirection, units byte, err error) {
+	status1, err = parseStatus(sentenceType, fields, index, "Status")
+	if err != nil {
+		return
+	}
+
+	status2, err = parseStatus(sentenceType, fields, index+1, "Status")
+	if err != nil {
+		return
+	}
+
+	crossTrackError, err = parseOptionalFloat(sentenceType, fields, index+2, "Cross Track Error")
+	if err != nil {
+		return
+	}
+
+	steerDirection, err = parseSteerDirection(sentenceType, fields, index+3)
+	if err != nil {
+		return
+	}
+
+	switch fields[index+4] {
+	case "N", "K":
+		units = fields[index+4][0]
+	case "":
+		if crossTrackError.Valid {
+			err = newFieldError(sentenceType, fields, index+4, "Units", fmt.Errorf("Failed to parse units %s", fields[index+4]))
+		}
+	default:
+		err = newFieldError(sentenceType, fields, index+4, "Units", fmt.Errorf("Failed to parse units %s", fields[index+4]))
+	}
+
+	return
+}
+
+// returns A when the value is true, or V otherwise.
+func formatStatus(value bool) string {
+	if value {
+		return "A"
+	}
+	return "V"
+}
+
+func (RMB) Type() string {
+	return "RMB"
+}
+
+func (s *RMB) String() string {
+	return marshalString(s)
+}
+
+func (s *RMB) marshalFields(e *Encoder) []string {
+	fields := make([]string, 13)
+
+	fields[0] = formatByte(s.Status)
+	fields[1] = e.formatOptionalFloat(s.CrossTrackError)
+	fields[2] = formatByte(s.SteerDirection)
+	fields[3] = s.OriginWaypointID
+	fields[4] = s.DestinationWaypointID
+	fields[5], fields[6], fields[7], fields[8] = e.formatPosition(s.DestinationLatitude, s.DestinationLongitude, s.CoordinatePrecision)
+	fields[9] = e.formatOptionalFloat(s.RangeNM)
+	fields[10] = e.formatOptionalFloat(s.Bearing)
+	fields[11] = e.formatOptionalFloat(s.ClosingVelocity)
+	fields[12] = formatStatus(s.Arrived)
+
+	if s.Mode != 0 {
+		fields = append(fields, formatByte(s.Mode))
+	}
+
+	return fields
+}
+
+func (APB) Type() string {
+	return "APB"
+}
+
+func (s *APB) String() string {
+	return marshalString(s)
+}
+
+func (s *APB) marshalFields(e *Encoder) []string {
+	fields := []string{
+		formatByte(s.Status1),
+		formatByte(s.Status2),
+		e.formatOptionalFloat(s.CrossTrackError),
+		formatByte(s.SteerDirection),
+		formatByte(s.CrossTrackUnits),
+		formatStatus(s.ArrivalCircleEntered),
+		formatStatus(s.PerpendicularPassed),
+		e.formatOptionalFloat(s.OriginBearing),
+		formatByte(s.OriginBearingReference),
+		s.DestinationWaypointID,
+		e.formatOptionalFloat(s.DestinationBearing),
+		formatByte(s.DestinationBearingReference),
+		e.formatOptionalFloat(s.HeadingToSteer),
+		formatByte(s.HeadingToSteerReference),
+	}
+
+	if s.Mode != 0 {
+		fields = append(fields, formatByte(s.Mode))
+	}
+
+	return fields
+}
+
+func (XTE) Type() string {
+	return "XTE"
+}
+
+func (s *XTE) String() string {
+	return marshalString(s)
+}
+
+func (s *XTE) marshalFields(e *Encoder) []string {
+	fields := []string{
+		formatByte(s.Status1),
+		formatByte(s.Status2),
+		e.formatOptionalFloat(s.CrossTrackError),
+		formatByte(s.SteerDirection),
+		formatByte(s.CrossTrackUnits),
+	}
+
+	if s.Mode != 0 {
+		fields = append(fields, formatByte(s.Mode))
+	}
+
+	return fields
+}
+
+func (BOD) Type() string {
+	return "BOD"
+}
+
+func (s *BOD) String() string {
+	return marshalString(s)
+}
+
+func (s *BOD) marshalFields(e *Encoder) []string {
+	return []string{
+		e.formatOptionalFloat(s.TrueBearing),
+		"T",
+		e.formatOptionalFloat(s.MagneticBearing),
+		"M",
+		s.DestinationWaypointID,
+		s.OriginWaypointID,
+	}
+}
+
+func (BWC) Type() string {
+	return "BWC"
+}
+
+func (s *BWC) String() string {
+	return marshalString(s)
+}
+
+func (s *BWC) marshalFields(e *Encoder) []string {
+	fields := make([]string, 12)
+
+	fields[0] = e.formatTime(s.Time)
+	fields[1], fields[2], fields[3], fields[4] = e.formatPosition(s.WaypointLatitude, s.WaypointLongitude, s.CoordinatePrecision)
+	fields[5] = e.formatOptionalFloat(s.TrueBearing)
+	fields[6] = "T"
+	fields[7] = e.formatOptionalFloat(s.MagneticBearing)
+	fields[8] = "M"
+	fields[9] = e.formatOptionalFloat(s.DistanceNM)
+	fields[10] = "N"
+	fields[11] = s.WaypointID
+
+	if s.Mode != 0 {
+		fields = append(fields, formatByte(s.Mode))
+	}
+
+	return fields
+}
+
+func (WPL) Type() string {
+	return "WPL"
+}
+
+func (s *WPL) String() string {
+	return marshalString(s)
+}
+
+func (s *WPL) marshalFields(e *Encoder) []string {
+	fields := make([]string, 5)
+
+	fields[0], fields[1], fields[2], fields[3] = e.formatPosition(s.Latitude, s.Longitude, s.CoordinatePrecision)
+	fields[4] = s.WaypointID
+
+	return fields
+}
@@ -4,61 +4,269 @@ package nmea
 
 import (
 	"bufio"
+	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"net"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 )
 
-// NB When PositionFix=0 you should only use the Time and UsedSatellites fields.
+// NB The optional fields are not Valid when they are absent from the sentence,
+// e.g. the Latitude and Longitude when PositionFix=0.
 type GPGGA struct {
-	Time           time.Duration
-	UsedSatellites byte
-	PositionFix    byte // 0=Fix not available. 1=GPS fix. 2=Differential GPS fix.
-	Latitude       float64
-	Longitude      float64
-	HDOP           float32
-	Altitude       float32 // in meters.
+	BaseSentence
+	Time                time.Duration
+	UsedSatellites      byte
+	PositionFix         FixQuality
+	Latitude            Optional[float64]
+	Longitude           Optional[float64]
+	CoordinatePrecision int // number of decimal digits of the Latitude and Longitude minutes in the sentence. e.g. 4 for ddmm.mmmm.
+	HDOP                Optional[float32]
+	Altitude            Optional[float32] // in meters. above/below the mean-sea-level (geoid).
+	GeoidSeparation     Optional[float32] // in meters. height of the geoid above the WGS84 ellipsoid.
+	DGPSAge             Optional[float32] // in seconds. age of the differential corrections. absent when DGPS is not used.
+	DGPSStationID       Optional[uint16]  // differential reference station ID. 0 to 1023.
+}
+
+// EllipsoidalHeight returns the height above the WGS84 ellipsoid in meters,
+// that is, the Altitude plus the GeoidSeparation. It's absent when any of
+// them is absent.
+func (s *GPGGA) EllipsoidalHeight() Optional[float32] {
+	if !s.Altitude.Valid || !s.GeoidSeparation.Valid {
+		return Optional[float32]{}
+	}
+	return Some(s.Altitude.Value + s.GeoidSeparation.Value)
+}
+
+// FixQuality is the GPGGA position fix indicator.
+type FixQuality byte
+
+const (
+	FixNotAvailable FixQuality = 0
+	FixGPS          FixQuality = 1
+	FixDGPS         FixQuality = 2 // Differential GPS fix.
+	FixPPS          FixQuality = 3 // Precise Positioning Service fix.
+	FixRTK          FixQuality = 4 // Real Time Kinematic fixed integers.
+	FixFloatRTK     FixQuality = 5 // Real Time Kinematic float integers.
+	FixEstimated    FixQuality = 6 // Estimated (dead reckoning) fix.
+	FixManual       FixQuality = 7 // Manual input mode.
+	FixSimulation   FixQuality = 8 // Simulation mode.
+)
+
+var fixQualityNames = []string{
+	"Not available",
+	"GPS",
+	"DGPS",
+	"PPS",
+	"RTK",
+	"Float RTK",
+	"Estimated",
+	"Manual",
+	"Simulation",
+}
+
+func (q FixQuality) String() string {
+	if int(q) < len(fixQualityNames) {
+		return fixQualityNames[q]
+	}
+	return fmt.Sprintf("FixQuality(%d)", byte(q))
 }
 
 type GPRMC struct {
-	Time      time.Time
-	Status    byte // A=data valid; V=data not valid.
-	Latitude  float64
-	Longitude float64
-	Mode      byte    // A=Autonomous mode; D=Differential mode; E=Estimated mode. N=NULL.
-	Speed     float32 // in knots.
-	Heading   float32 // in degrees.
+	BaseSentence
+	Time                time.Time
+	Status              byte // A=data valid; V=data not valid.
+	Latitude            Optional[float64]
+	Longitude           Optional[float64]
+	CoordinatePrecision int               // number of decimal digits of the Latitude and Longitude minutes in the sentence. e.g. 4 for ddmm.mmmm.
+	Mode                byte              // A=Autonomous mode; D=Differential mode; E=Estimated mode. N=NULL; F=Float RTK mode; R=RTK mode; M=Manual input mode; S=Simulator mode; P=Precise mode.
+	Speed               Optional[float32] // in knots.
+	Heading             Optional[float32] // in degrees.
+	MagneticVariation   Optional[float32] // in degrees. east is positive and west is negative.
+	NavigationalStatus  byte              // S=Safe; C=Caution; U=Unsafe; V=Not valid. 0 before NMEA 4.1.
 }
 
 type GPGSA struct {
-	Mode1 byte // M=Manual; A=Automatic
-	Mode2 byte // 1=No fix; 2=2D (<4 used SVs); 3=3D (>=4 used SVs)
-	SVs   []byte
-	PDOP  float32
-	HDOP  float32
-	VDOP  float32
+	BaseSentence
+	Mode1    byte // M=Manual; A=Automatic
+	Mode2    byte // 1=No fix; 2=2D (<4 used SVs); 3=3D (>=4 used SVs)
+	SVs      []byte
+	PDOP     Optional[float32]
+	HDOP     Optional[float32]
+	VDOP     Optional[float32]
+	SystemID Optional[byte] // NMEA 4.10 GNSS system ID. 1=GPS; 2=GLONASS; 3=Galileo; 4=BeiDou; 5=QZSS; 6=NavIC.
+}
+
+func (GPGGA) Type() string {
+	return "GGA"
+}
+
+func (s *GPGGA) String() string {
+	return marshalString(s)
+}
+
+func (s *GPGGA) marshalFields(e *Encoder) []string {
+	fields := make([]string, 14)
+
+	fields[0] = e.formatTime(s.Time)
+
+	fields[1], fields[2], fields[3], fields[4] = e.formatPosition(s.Latitude, s.Longitude, s.CoordinatePrecision)
+
+	fields[5] = strconv.Itoa(int(s.PositionFix))
+	fields[6] = strconv.Itoa(int(s.UsedSatellites))
+	fields[7] = e.formatOptionalFloat(s.HDOP)
+	fields[8] = e.formatOptionalFloat(s.Altitude)
+	fields[9] = "M"
+	fields[10] = e.formatOptionalFloat(s.GeoidSeparation)
+	fields[11] = "M"
+	fields[12] = e.formatOptionalFloat(s.DGPSAge)
+
+	if s.DGPSStationID.Valid {
+		fields[13] = fmt.Sprintf("%04d", s.DGPSStationID.Value)
+	}
+
+	return fields
+}
+
+func (GPRMC) Type() string {
+	return "RMC"
+}
+
+func (s *GPRMC) String() string {
+	return marshalString(s)
+}
+
+func (s *GPRMC) marshalFields(e *Encoder) []string {
+	fields := make([]string, 12)
+
+	fields[0] = e.formatTime(timeOfDay(s.Time))
+	fields[1] = formatByte(s.Status)
+
+	fields[2], fields[3], fields[4], fields[5] = e.formatPosition(s.Latitude, s.Longitude, s.CoordinatePrecision)
+
+	fields[6] = e.formatOptionalFloat(s.Speed)
+	fields[7] = e.formatOptionalFloat(s.Heading)
+	fields[8] = formatDate(s.Time)
+	fields[11] = formatByte(s.Mode)
+
+	if magneticVariation, ok := s.MagneticVariation.Get(); ok {
+		fields[9] = e.formatFloat(float32(math.Abs(float64(magneticVariation))))
+		fields[10] = "E"
+		if magneticVariation < 0 {
+			fields[10] = "W"
+		}
+	}
+
+	if s.NavigationalStatus != 0 {
+		fields = append(fields, formatByte(s.NavigationalStatus))
+	}
+
+	return fields
+}
+
+func (GPGSA) Type() string {
+	return "GSA"
+}
+
+func (s *GPGSA) String() string {
+	return marshalString(s)
+}
+
+func (s *GPGSA) marshalFields(e *Encoder) []string {
+	fields := make([]string, 17)
+
+	fields[0] = formatByte(s.Mode1)
+	fields[1] = formatByte(s.Mode2)
+
+	for i, sv := range s.SVs {
+		if i == 12 {
+			break
+		}
+		fields[2+i] = fmt.Sprintf("%02d", sv)
+	}
+
+	fields[14] = e.formatOptionalFloat(s.PDOP)
+	fields[15] = e.formatOptionalFloat(s.HDOP)
+	fields[16] = e.formatOptionalFloat(s.VDOP)
+
+	if s.SystemID.Valid {
+		fields = append(fields, strconv.FormatUint(uint64(s.SystemID.Value), 16))
+	}
+
+	return fields
 }
 
 func isValidSentence(sentence string) bool {
+	return checkSentence(sentence) == nil
+}
+
+// checks the sentence framing and checksum. returns a *ChecksumError when
+// the sentence is not valid.
+func checkSentence(sentence string) error {
 	l := len(sentence)
 
-	// the minimum length accepted sentence is $T,*CC.
-	if l < 6 || sentence[0] != '$' || sentence[l-3] != '*' {
-		return false
+	// the minimum length accepted sentence is $T,*CC. encapsulated sentences,
+	// i.e. the AIS VDM and VDO, start with ! instead of $.
+	if l < 6 || (sentence[0] != '$' && sentence[0] != '!') || sentence[l-3] != '*' {
+		return &ChecksumError{Sentence: sentence}
+	}
+
+	if sentence[0] == '!' && !isEncapsulatedSentenceType(sentenceTypeOf(sentence)) {
+		return &ChecksumError{Sentence: sentence}
+	}
+
+	checksum := computeChecksum(sentence[1 : l-3])
+
+	expectedChecksum := sentence[l-2 : l]
+
+	expectedChecksumBytes, err := hex.DecodeString(expectedChecksum)
+
+	if err != nil || checksum != expectedChecksumBytes[0] {
+		return &ChecksumError{Sentence: sentence, Expected: expectedChecksum, Actual: checksum}
 	}
 
+	return nil
+}
+
+func isEncapsulatedSentenceType(sentenceType string) bool {
+	_, formatter := splitSentenceType(sentenceType)
+
+	return formatter == "VDM" || formatter == "VDO"
+}
+
+// computes the checksum of the sentence data, that is, the characters
+// between the $ (or !) and the *.
+func computeChecksum(data string) byte {
 	checksum := byte(0)
 
-	for i := 1; i < l-3; i++ {
-		checksum = checksum ^ byte(sentence[i])
+	for i := 0; i < len(data); i++ {
+		checksum = checksum ^ byte(data[i])
 	}
 
-	expectedChecksumBytes, err := hex.DecodeString(sentence[l-2 : l])
+	return checksum
+}
+
+// returns the sentence type. e.g.: GPGGA for $GPGGA,064951.000,...
+// this also works for malformed sentences, in which case the returned type
+// is just a best effort guess.
+func sentenceTypeOf(sentence string) string {
+	sentenceType := sentence
 
-	return err == nil && checksum == expectedChecksumBytes[0]
+	if len(sentenceType) > 0 && (sentenceType[0] == '$' || sentenceType[0] == '!') {
+		sentenceType = sentenceType[1:]
+	}
+
+	if i := strings.IndexAny(sentenceType, ",*"); i >= 0 {
+		sentenceType = sentenceType[:i]
+	}
+
+	return sentenceType
 }
 
 // Global Positioning System Fixed Data. Time, Position and fix.
@@ -84,6 +292,12 @@ func isValidSentence(sentence string) bool {
 // |  5 | Position Fix  | 1          |        | 0=Fix not available           |
 // |    |               |            |        | 1=GPS fix                     |
 // |    |               |            |        | 2=Differential GPS fix        |
+// |    |               |            |        | 3=PPS fix                     |
+// |    |               |            |        | 4=RTK fixed                   |
+// |    |               |            |        | 5=RTK float                   |
+// |    |               |            |        | 6=Estimated (dead reckoning)  |
+// |    |               |            |        | 7=Manual input mode           |
+// |    |               |            |        | 8=Simulation mode             |
 // |  6 | Satellites    | 8          |        | Range 0 to 12                 |
 // |    | Used          |            |        |                               |
 // |  7 | HDOP          | 0.95       |        | Horizontal Dilution of        |
@@ -96,22 +310,23 @@ func isValidSentence(sentence string) bool {
 // | 11 | Units         | M          | meters | Units of geoids separation    |
 // | 12 | Age of Diff.  |            | second | Null fields when DGPS is not  |
 // |    | Corr.         |            |        | used                          |
-// | 13 | unknown       |            |        |                               |
+// | 13 | Diff. Ref.    |            |        | Range 0000 to 1023. Null      |
+// |    | Station ID    |            |        | fields when DGPS is not used  |
 // +----+---------------+------------+--------+-------------------------------+
-func parseGPGGA(sentence string) (*GPGGA, error) {
-	result := &GPGGA{}
+func parseGPGGA(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
 
-	fields := splitFields(sentence)
+	result := &GPGGA{BaseSentence: base}
 
 	if len(fields) != 14 {
-		return nil, fmt.Errorf("Failed to parse GPGGA. invalid number of fields %v", len(fields))
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
 	}
 
 	//
 	// time. e.g.: 064951.000 format: hhmmss.sss
 	timeMs, err := parseTime(fields[0])
 	if err != nil {
-		return nil, err
+		return nil, newFieldError(sentenceType, fields, 0, "UTC Time", err)
 	}
 
 	result.Time = time.Duration(timeMs) * time.Millisecond
@@ -119,33 +334,20 @@ func parseGPGGA(sentence string) (*GPGGA, error) {
 	//
 	// latitude.  if available. e.g.: 2307.1256  format: ddmm.mmmm
 	// longitude. if available. e.g.: 12016.4438 format: dddmm.mmmm
-	latitudeField := fields[1]
-	longitudeField := fields[3]
-
-	if len(latitudeField) > 0 && len(longitudeField) > 0 {
-		latitude, err := parseLatitude(latitudeField, fields[2])
-		if err != nil {
-			return nil, err
-		}
-
-		longitude, err := parseLongitude(longitudeField, fields[4])
-		if err != nil {
-			return nil, err
-		}
-
-		result.Latitude = latitude
-		result.Longitude = longitude
-	}
-
-	positionFix, err := strconv.ParseInt(fields[5], 10, 8)
+	result.Latitude, result.Longitude, result.CoordinatePrecision, err = parsePosition(sentenceType, fields, 1)
 	if err != nil {
 		return nil, err
 	}
-	result.PositionFix = byte(positionFix)
+
+	positionFix, err := strconv.ParseUint(fields[5], 10, 8)
+	if err != nil || positionFix > uint64(FixSimulation) {
+		return nil, newFieldError(sentenceType, fields, 5, "Position Fix", fmt.Errorf("Failed to parse position fix %s", fields[5]))
+	}
+	result.PositionFix = FixQuality(positionFix)
 
 	usedSatellites, err := strconv.ParseInt(fields[6], 10, 8)
 	if err != nil {
-		return nil, err
+		return nil, newFieldError(sentenceType, fields, 6, "Satellites Used", err)
 	}
 	result.UsedSatellites = byte(usedSatellites)
 
@@ -153,22 +355,53 @@ func parseGPGGA(sentence string) (*GPGGA, error) {
 	if len(hdopField) > 0 {
 		hdop, err := strconv.ParseFloat(hdopField, 32)
 		if err != nil {
-			return nil, err
+			return nil, newFieldError(sentenceType, fields, 7, "HDOP", err)
 		}
-		result.HDOP = float32(hdop)
+		result.HDOP = Some(float32(hdop))
 	}
 
 	altitudeField := fields[8]
 	if len(altitudeField) > 0 {
 		altitude, err := strconv.ParseFloat(altitudeField, 32)
 		if err != nil {
-			return nil, err
+			return nil, newFieldError(sentenceType, fields, 8, "MSL Altitude", err)
 		}
-		result.Altitude = float32(altitude)
+		result.Altitude = Some(float32(altitude))
 	}
 
 	if fields[9] != "M" {
-		return nil, fmt.Errorf("Altitude unit not supported: %s", fields[9])
+		return nil, newFieldError(sentenceType, fields, 9, "Units", fmt.Errorf("Altitude unit not supported: %s", fields[9]))
+	}
+
+	geoidSeparationField := fields[10]
+	if len(geoidSeparationField) > 0 {
+		geoidSeparation, err := strconv.ParseFloat(geoidSeparationField, 32)
+		if err != nil {
+			return nil, newFieldError(sentenceType, fields, 10, "Geoidal Separation", err)
+		}
+		result.GeoidSeparation = Some(float32(geoidSeparation))
+
+		if fields[11] != "M" {
+			return nil, newFieldError(sentenceType, fields, 11, "Units", fmt.Errorf("Geoidal separation unit not supported: %s", fields[11]))
+		}
+	}
+
+	dgpsAgeField := fields[12]
+	if len(dgpsAgeField) > 0 {
+		dgpsAge, err := strconv.ParseFloat(dgpsAgeField, 32)
+		if err != nil {
+			return nil, newFieldError(sentenceType, fields, 12, "Age of Diff. Corr.", err)
+		}
+		result.DGPSAge = Some(float32(dgpsAge))
+	}
+
+	dgpsStationIDField := fields[13]
+	if len(dgpsStationIDField) > 0 {
+		dgpsStationID, err := strconv.ParseUint(dgpsStationIDField, 10, 16)
+		if err != nil || dgpsStationID > 1023 {
+			return nil, newFieldError(sentenceType, fields, 13, "Diff. Ref. Station ID", fmt.Errorf("Failed to parse station ID %s", dgpsStationIDField))
+		}
+		result.DGPSStationID = Some(uint16(dgpsStationID))
 	}
 
 	return result, nil
@@ -211,28 +444,38 @@ func parseGPGGA(sentence string) (*GPGGA, error) {
 // |    |               |            |         | N=NULL (I didn't see this on |
 // |    |               |            |         |   the datasheet, but on a    |
 // |    |               |            |         |   real device)               |
+// |    |               |            |         | F=Float RTK mode             |
+// |    |               |            |         | R=RTK mode                   |
+// |    |               |            |         | M=Manual input mode          |
+// |    |               |            |         | S=Simulator mode             |
+// |    |               |            |         | P=Precise mode               |
+// | 12 | Navigational  | V          |         | S=Safe                       |
+// |    | Status        |            |         | C=Caution                    |
+// |    |               |            |         | U=Unsafe                     |
+// |    |               |            |         | V=Not valid                  |
+// |    |               |            |         | Only in NMEA 4.1 and later   |
 // +----+---------------+------------+---------+------------------------------+
-func parseGPRMC(sentence string) (*GPRMC, error) {
-	result := &GPRMC{}
+func parseGPRMC(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
 
-	fields := splitFields(sentence)
+	result := &GPRMC{BaseSentence: base}
 
-	if len(fields) != 12 {
-		return nil, fmt.Errorf("Failed to parse GPRMC. invalid number of fields %v", len(fields))
+	if len(fields) != 12 && len(fields) != 13 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
 	}
 
 	//
 	// time. e.g.: 064951.000 format: hhmmss.sss
 	timeMs, err := parseTime(fields[0])
 	if err != nil {
-		return nil, err
+		return nil, newFieldError(sentenceType, fields, 0, "UTC Time", err)
 	}
 
 	//
 	// date. e.g.: 260406 format: ddmmyy
-	date, err := parseDate(fields[8])
+	date, err := parseDate(fields[8], dates)
 	if err != nil {
-		return nil, err
+		return nil, newFieldError(sentenceType, fields, 8, "Date", err)
 	}
 
 	result.Time = date.Add(time.Duration(timeMs) * time.Millisecond)
@@ -240,13 +483,13 @@ func parseGPRMC(sentence string) (*GPRMC, error) {
 	//
 	// status.
 	if len(fields[1]) != 1 {
-		return nil, fmt.Errorf("Failed to parse status %s", fields[1])
+		return nil, newFieldError(sentenceType, fields, 1, "Status", fmt.Errorf("Failed to parse status %s", fields[1]))
 	}
 
 	status := byte(fields[1][0])
 
 	if status != 'A' && status != 'V' {
-		return nil, fmt.Errorf("Failed to parse status %s", status)
+		return nil, newFieldError(sentenceType, fields, 1, "Status", fmt.Errorf("Failed to parse status %c", status))
 	}
 
 	result.Status = status
@@ -254,57 +497,81 @@ func parseGPRMC(sentence string) (*GPRMC, error) {
 	//
 	// latitude.  if available. e.g.: 2307.1256  format: ddmm.mmmm
 	// longitude. if available. e.g.: 12016.4438 format: dddmm.mmmm
-	latitudeField := fields[2]
-	longitudeField := fields[4]
+	result.Latitude, result.Longitude, result.CoordinatePrecision, err = parsePosition(sentenceType, fields, 2)
+	if err != nil {
+		return nil, err
+	}
 
-	if len(latitudeField) > 0 && len(longitudeField) > 0 {
-		latitude, err := parseLatitude(latitudeField, fields[3])
+	//
+	// speed. if available.
+	if len(fields[6]) > 0 {
+		speed, err := strconv.ParseFloat(fields[6], 32)
 		if err != nil {
-			return nil, err
+			return nil, newFieldError(sentenceType, fields, 6, "Speed over Ground", err)
 		}
+		result.Speed = Some(float32(speed))
+	}
 
-		longitude, err := parseLongitude(longitudeField, fields[5])
+	//
+	// heading. if available.
+	if len(fields[7]) > 0 {
+		heading, err := strconv.ParseFloat(fields[7], 32)
 		if err != nil {
-			return nil, err
+			return nil, newFieldError(sentenceType, fields, 7, "Course over Ground", err)
 		}
-
-		result.Latitude = latitude
-		result.Longitude = longitude
+		result.Heading = Some(float32(heading))
 	}
 
 	//
-	// speed.
-	speed, err := strconv.ParseFloat(fields[6], 32)
+	// mode.
+	result.Mode, err = parseMode(sentenceType, fields, 11)
 	if err != nil {
 		return nil, err
 	}
-	result.Speed = float32(speed)
 
 	//
-	// heading.
-	heading, err := strconv.ParseFloat(fields[7], 32)
+	// magnetic variation.
+	result.MagneticVariation, err = parseMagneticAngle(sentenceType, fields, 9, "Magnetic Variation")
 	if err != nil {
 		return nil, err
 	}
-	result.Heading = float32(heading)
 
 	//
-	// mode.
-	if len(fields[11]) != 1 {
-		return nil, fmt.Errorf("Failed to parse mode %v", fields[11])
-	}
+	// navigational status. only in NMEA 4.1 and later.
+	if len(fields) == 13 {
+		if len(fields[12]) != 1 {
+			return nil, newFieldError(sentenceType, fields, 12, "Navigational Status", fmt.Errorf("Failed to parse navigational status %v", fields[12]))
+		}
 
-	mode := byte(fields[11][0])
+		navigationalStatus := byte(fields[12][0])
 
-	if mode != 'A' && mode != 'D' && mode != 'E' && mode != 'N' {
-		return nil, fmt.Errorf("Failed to parse mode %v", mode)
-	}
+		if navigationalStatus != 'S' && navigationalStatus != 'C' && navigationalStatus != 'U' && navigationalStatus != 'V' {
+			return nil, newFieldError(sentenceType, fields, 12, "Navigational Status", fmt.Errorf("Failed to parse navigational status %c", navigationalStatus))
+		}
 
-	result.Mode = mode
+		result.NavigationalStatus = navigationalStatus
+	}
 
 	return result, nil
 }
 
+// returns whether the byte is a valid positioning system mode indicator.
+func isValidMode(mode byte) bool {
+	switch mode {
+	case 'A', 'D', 'E', 'N', 'F', 'R', 'M', 'S', 'P':
+		return true
+	}
+	return false
+}
+
+// parses a positioning system mode indicator field.
+func parseMode(sentenceType string, fields []string, index int) (byte, error) {
+	if len(fields[index]) != 1 || !isValidMode(fields[index][0]) {
+		return 0, newFieldError(sentenceType, fields, index, "Mode", fmt.Errorf("Failed to parse mode %s", fields[index]))
+	}
+	return fields[index][0], nil
+}
+
 // Global Positioning GNSS DOP and Active Satellites
 //
 // Example:
@@ -328,42 +595,44 @@ func parseGPRMC(sentence string) (*GPRMC, error) {
 // | 14 | PDOP   | 2.32    | Position Dilution of Precision   |
 // | 15 | HDOP   | 0.95    | Horizontal Dilution of Precision |
 // | 16 | VDOP   | 2.11    | Vertical Dilution of Precision   |
+// | 17 | System | 1       | GNSS System ID                   |
+// |    | ID     |         | Only in NMEA 4.10 and later      |
 // +----+--------+---------+----------------------------------+
-func parseGPGSA(sentence string) (*GPGSA, error) {
-	result := &GPGSA{}
+func parseGPGSA(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
 
-	fields := splitFields(sentence)
+	result := &GPGSA{BaseSentence: base}
 
-	if len(fields) != 17 {
-		return nil, fmt.Errorf("Failed to parse GPGSA. invalid number of fields %v", len(fields))
+	if len(fields) != 17 && len(fields) != 18 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
 	}
 
 	//
 	// mode 1.
 	if len(fields[0]) != 1 {
-		return nil, fmt.Errorf("Failed to parse mode1 %s", fields[0])
+		return nil, newFieldError(sentenceType, fields, 0, "Mode 1", fmt.Errorf("Failed to parse mode1 %s", fields[0]))
 	}
 	mode1 := byte(fields[0][0])
 	if mode1 != 'M' && mode1 != 'A' {
-		return nil, fmt.Errorf("Failed to parse mode1 %s", mode1)
+		return nil, newFieldError(sentenceType, fields, 0, "Mode 1", fmt.Errorf("Failed to parse mode1 %c", mode1))
 	}
 	result.Mode1 = mode1
 
 	//
 	// mode 2.
 	if len(fields[1]) != 1 {
-		return nil, fmt.Errorf("Failed to parse mode2 %s", fields[1])
+		return nil, newFieldError(sentenceType, fields, 1, "Mode 2", fmt.Errorf("Failed to parse mode2 %s", fields[1]))
 	}
 	mode2 := byte(fields[1][0])
 	if mode2 != '1' && mode2 != '2' && mode2 != '3' {
-		return nil, fmt.Errorf("Failed to parse mode2 %s", mode2)
+		return nil, newFieldError(sentenceType, fields, 1, "Mode 2", fmt.Errorf("Failed to parse mode2 %c", mode2))
 	}
 	result.Mode2 = mode2
 
 	//
 	// SVs.
 	usedSVs := 0
-	for i := 2; i < 12; i++ {
+	for i := 2; i < 14; i++ {
 		if len(fields[i]) == 0 {
 			break
 		}
@@ -372,108 +641,197 @@ func parseGPGSA(sentence string) (*GPGSA, error) {
 	svs := make([]byte, usedSVs)
 	for i := 0; i < usedSVs; i++ {
 		svField := fields[2+i]
-		sv, err := strconv.ParseInt(svField, 10, 8)
+		sv, err := strconv.ParseUint(svField, 10, 8)
 		if err != nil {
-			return nil, fmt.Errorf("Failed to parse SV Channel %v %s", i, svField)
+			return nil, newFieldError(sentenceType, fields, 2+i, fmt.Sprintf("SV %d", i+1), err)
 		}
 		svs[i] = byte(sv)
 	}
 	result.SVs = svs
 
-	// the xDOP fields are only available when there is a fix.
-	if result.Mode2 != '1' {
-		//
-		// PDOP.
-		pdop, err := strconv.ParseFloat(fields[14], 32)
-		if err != nil {
-			return nil, err
-		}
-		result.PDOP = float32(pdop)
+	//
+	// PDOP, HDOP and VDOP. usually only available when there is a fix.
+	dops := []struct {
+		name  string
+		value *Optional[float32]
+	}{
+		{"PDOP", &result.PDOP},
+		{"HDOP", &result.HDOP},
+		{"VDOP", &result.VDOP},
+	}
 
-		//
-		// HDOP.
-		hdop, err := strconv.ParseFloat(fields[15], 32)
+	for i, dop := range dops {
+		field := fields[14+i]
+		if len(field) == 0 {
+			continue
+		}
+		value, err := strconv.ParseFloat(field, 32)
 		if err != nil {
-			return nil, err
+			return nil, newFieldError(sentenceType, fields, 14+i, dop.name, err)
 		}
-		result.HDOP = float32(hdop)
+		*dop.value = Some(float32(value))
+	}
 
-		//
-		// VDOP.
-		vdop, err := strconv.ParseFloat(fields[16], 32)
+	//
+	// GNSS system ID. only in NMEA 4.10 and later.
+	if len(fields) == 18 && len(fields[17]) > 0 {
+		systemID, err := strconv.ParseUint(fields[17], 16, 8)
 		if err != nil {
-			return nil, err
+			return nil, newFieldError(sentenceType, fields, 17, "System ID", err)
 		}
-		result.VDOP = float32(vdop)
+		result.SystemID = Some(byte(systemID))
 	}
 
 	return result, nil
 }
 
-// latitude. format: ddmm.mmmm e.g.: input: 2307.1256 output: 23.11876
+// parses the latitude, N/S indicator, longitude and E/W indicator fields
+// that start at index. the position is absent when the latitude or the
+// longitude is empty.
+func parsePosition(sentenceType string, fields []string, index int) (Optional[float64], Optional[float64], int, error) {
+	if len(fields[index]) == 0 || len(fields[index+2]) == 0 {
+		return Optional[float64]{}, Optional[float64]{}, 0, nil
+	}
+
+	latitude, latitudePrecision, err := parseLatitude(fields[index], fields[index+1])
+	if err != nil {
+		return Optional[float64]{}, Optional[float64]{}, 0, newFieldError(sentenceType, fields, index, "Latitude", err)
+	}
+
+	longitude, longitudePrecision, err := parseLongitude(fields[index+2], fields[index+3])
+	if err != nil {
+		return Optional[float64]{}, Optional[float64]{}, 0, newFieldError(sentenceType, fields, index+2, "Longitude", err)
+	}
+
+	return Some(latitude), Some(longitude), max(latitudePrecision, longitudePrecision), nil
+}
+
+// latitude. format: ddmm.mmmm e.g.: input: 2307.1256 output: 23.11876 4
 // indicator. e.g.: N
-func parseLatitude(text string, indicator string) (float64, error) {
-	if len(text) != 9 || text[4] != '.' {
-		return 0, fmt.Errorf("Failed to parse latitude %s", text)
+// the minutes can have any number of decimal digits, e.g. 2307.1256789 or
+// 2307.12, the number of decimal digits is also returned.
+func parseLatitude(text string, indicator string) (float64, int, error) {
+	precision, ok := coordinatePrecision(text, 2)
+	if !ok {
+		return 0, 0, fmt.Errorf("Failed to parse latitude %s", text)
 	}
 
 	if indicator != "N" && indicator != "S" {
-		return 0, fmt.Errorf("Failed to parse latitude indicator %s", indicator)
+		return 0, 0, fmt.Errorf("Failed to parse latitude indicator %s", indicator)
 	}
 
 	degrees, err := strconv.ParseFloat(text[0:2], 64)
 	if err != nil {
-		return 0, fmt.Errorf("Failed to parse latitude degrees %s: %v", text, err)
+		return 0, 0, fmt.Errorf("Failed to parse latitude degrees %s: %v", text, err)
 	}
 
 	minutes, err := strconv.ParseFloat(text[2:], 64)
 	if err != nil {
-		return 0, fmt.Errorf("Failed to parse latitude minutes %s: %v", text, err)
+		return 0, 0, fmt.Errorf("Failed to parse latitude minutes %s: %v", text, err)
+	}
+
+	if minutes >= 60 {
+		return 0, 0, fmt.Errorf("Failed to parse latitude minutes %s: out of range", text)
 	}
 
 	latitude := degrees + minutes/60
 
+	if latitude > 90 {
+		return 0, 0, fmt.Errorf("Failed to parse latitude %s: out of range", text)
+	}
+
 	if indicator == "S" {
 		latitude *= -1
 	}
 
-	return latitude, nil
+	return latitude, precision, nil
 }
 
-// longitude. format: dddmm.mmmm e.g.: input: 12016.4438 output: 120.274063333333334
+// longitude. format: dddmm.mmmm e.g.: input: 12016.4438 output: 120.274063333333334 4
 // indicator. e.g.: E
-func parseLongitude(text string, indicator string) (float64, error) {
-	if len(text) != 10 || text[5] != '.' {
-		return 0, fmt.Errorf("Failed to parse longitude %s", text)
+// the minutes can have any number of decimal digits, e.g. 12016.4438123 or
+// 12016.44, the number of decimal digits is also returned.
+func parseLongitude(text string, indicator string) (float64, int, error) {
+	precision, ok := coordinatePrecision(text, 3)
+	if !ok {
+		return 0, 0, fmt.Errorf("Failed to parse longitude %s", text)
 	}
 
 	if indicator != "E" && indicator != "W" {
-		return 0, fmt.Errorf("Failed to parse longitude indicator %s", indicator)
+		return 0, 0, fmt.Errorf("Failed to parse longitude indicator %s", indicator)
 	}
 
 	degrees, err := strconv.ParseFloat(text[0:3], 64)
 	if err != nil {
-		return 0, fmt.Errorf("Failed to parse longitude degrees %s: %v", text, err)
+		return 0, 0, fmt.Errorf("Failed to parse longitude degrees %s: %v", text, err)
 	}
 
 	minutes, err := strconv.ParseFloat(text[3:], 64)
 	if err != nil {
-		return 0, fmt.Errorf("Failed to parse longitude minutes %s: %v", text, err)
+		return 0, 0, fmt.Errorf("Failed to parse longitude minutes %s: %v", text, err)
+	}
+
+	if minutes >= 60 {
+		return 0, 0, fmt.Errorf("Failed to parse longitude minutes %s: out of range", text)
 	}
 
 	longitude := degrees + minutes/60
 
+	if longitude > 180 {
+		return 0, 0, fmt.Errorf("Failed to parse longitude %s: out of range", text)
+	}
+
 	if indicator == "W" {
 		longitude *= -1
 	}
 
-	return longitude, nil
+	return longitude, precision, nil
+}
+
+// checks that the coordinate has the degrees and minutes digits, and an
+// optional decimal point followed by the minutes decimal digits. returns the
+// number of minutes decimal digits.
+func coordinatePrecision(text string, degreesDigits int) (int, bool) {
+	dot := degreesDigits + 2
+
+	if len(text) < dot || (len(text) > dot && text[dot] != '.') {
+		return 0, false
+	}
+
+	for i := 0; i < len(text); i++ {
+		if i != dot && (text[i] < '0' || text[i] > '9') {
+			return 0, false
+		}
+	}
+
+	if len(text) == dot {
+		return 0, true
+	}
+
+	return len(text) - dot - 1, true
+}
+
+// parses an optional decimal field. returns an absent value when the field is
+// empty.
+func parseOptionalFloat(sentenceType string, fields []string, index int, name string) (Optional[float32], error) {
+	if len(fields[index]) == 0 {
+		return Optional[float32]{}, nil
+	}
+
+	value, err := strconv.ParseFloat(fields[index], 32)
+	if err != nil {
+		return Optional[float32]{}, newFieldError(sentenceType, fields, index, name, err)
+	}
+
+	return Some(float32(value)), nil
 }
 
 // parse time. format: hhmmss.sss e.g. 064951.000
+// the seconds can have any number of decimal digits, e.g. 064951.00 or
+// 064951, but they are truncated to milliseconds.
 func parseTime(text string) (int32, error) {
-	if len(text) != 10 {
-		return 0, fmt.Errorf("Failed to parse time %s: len is not 10", text)
+	if len(text) < 6 || (len(text) > 6 && text[6] != '.') {
+		return 0, fmt.Errorf("Failed to parse time %s: format is not hhmmss.sss", text)
 	}
 
 	h, err := strconv.ParseInt(text[0:2], 10, 8)
@@ -491,16 +849,24 @@ func parseTime(text string) (int32, error) {
 		return 0, fmt.Errorf("Failed to parse time %s: minute could not be parsed due to %v", text, err)
 	}
 
-	ms, err := strconv.ParseInt(text[7:10], 10, 16)
-	if err != nil {
-		return 0, fmt.Errorf("Failed to parse time %s: milliseconds could not be parsed due to %v", text, err)
+	var ms int64
+
+	if len(text) > 7 {
+		fraction := text[7:]
+
+		if strings.Trim(fraction, "0123456789") != "" {
+			return 0, fmt.Errorf("Failed to parse time %s: milliseconds could not be parsed", text)
+		}
+
+		ms, _ = strconv.ParseInt((fraction + "00")[:3], 10, 16)
 	}
 
 	return int32(ms) + int32(s)*1000 + int32(m)*1000*60 + int32(h)*1000*60*60, nil
 }
 
 // parse date. e.g.: 260406 format: ddmmyy
-func parseDate(text string) (time.Time, error) {
+// the century and the GPS week rollover are resolved by dates.
+func parseDate(text string, dates DatePolicy) (time.Time, error) {
 	if len(text) != 6 {
 		return time.Time{}, fmt.Errorf("Failed to parse date %s: len is not 6", text)
 	}
@@ -520,7 +886,7 @@ func parseDate(text string) (time.Time, error) {
 		return time.Time{}, fmt.Errorf("Failed to parse date %s: year could not be parsed due to %v", text, err)
 	}
 
-	return time.Date(2000+int(y), time.Month(m), int(d), 0, 0, 0, 0, time.UTC), nil
+	return dates.Date(int(y), int(m), int(d))
 }
 
 func splitFields(sentence string) []string {
@@ -540,67 +906,348 @@ func splitFields(sentence string) []string {
 	return fields[1:]
 }
 
+// Visitor receives the sentences parsed by Visit. Embed a BaseVisitor to
+// only implement some of its methods.
 type Visitor interface {
 	OnBeforeParse(sentenceType, sentence string) bool
 	OnAfterParse(sentenceType, sentence string, err error)
 	OnGPGGA(sentence *GPGGA)
 	OnGPRMC(sentence *GPRMC)
 	OnGPGSA(sentence *GPGSA)
+	OnGPGSV(sentence *GPGSV)
+	OnGPVTG(sentence *GPVTG)
+	OnGPZDA(sentence *GPZDA)
+	OnGPGLL(sentence *GPGLL)
+	OnGNGNS(sentence *GNGNS)
+	OnGPGST(sentence *GPGST)
+	OnHDT(sentence *HDT)
+	OnHDM(sentence *HDM)
+	OnHDG(sentence *HDG)
+	OnTHS(sentence *THS)
+	OnROT(sentence *ROT)
+	OnDBT(sentence *DBT)
+	OnDPT(sentence *DPT)
+	OnMTW(sentence *MTW)
+	OnVHW(sentence *VHW)
+	OnVLW(sentence *VLW)
+	OnMWV(sentence *MWV)
+	OnMWD(sentence *MWD)
+	OnRMB(sentence *RMB)
+	OnAPB(sentence *APB)
+	OnXTE(sentence *XTE)
+	OnBOD(sentence *BOD)
+	OnBWC(sentence *BWC)
+	OnWPL(sentence *WPL)
+	OnRTE(sentence *RTE)
+	OnVDM(sentence *VDM)
+	// OnSentence is called with the sentences of the types registered with
+	// RegisterParser.
+	OnSentence(sentence Sentence)
+}
+
+// BaseVisitor implements all the Visitor methods doing nothing, except
+// OnBeforeParse, which returns true to parse all the sentences. Embed it in a
+// visitor to only implement the methods of the sentences of interest, and to
+// keep compiling when methods are added to Visitor, e.g.:
+//
+//	type ggaVisitor struct {
+//		nmea.BaseVisitor
+//	}
+//
+//	func (v *ggaVisitor) OnGPGGA(s *nmea.GPGGA) {
+//		...
+//	}
+type BaseVisitor struct{}
+
+func (BaseVisitor) OnBeforeParse(sentenceType, sentence string) bool {
+	return true
+}
+
+func (BaseVisitor) OnAfterParse(sentenceType, sentence string, err error) {}
+
+func (BaseVisitor) OnGPGGA(sentence *GPGGA) {}
+
+func (BaseVisitor) OnGPRMC(sentence *GPRMC) {}
+
+func (BaseVisitor) OnGPGSA(sentence *GPGSA) {}
+
+func (BaseVisitor) OnGPGSV(sentence *GPGSV) {}
+
+func (BaseVisitor) OnGPVTG(sentence *GPVTG) {}
+
+func (BaseVisitor) OnGPZDA(sentence *GPZDA) {}
+
+func (BaseVisitor) OnGPGLL(sentence *GPGLL) {}
+
+func (BaseVisitor) OnGNGNS(sentence *GNGNS) {}
+
+func (BaseVisitor) OnGPGST(sentence *GPGST) {}
+
+func (BaseVisitor) OnHDT(sentence *HDT) {}
+
+func (BaseVisitor) OnHDM(sentence *HDM) {}
+
+func (BaseVisitor) OnHDG(sentence *HDG) {}
+
+func (BaseVisitor) OnTHS(sentence *THS) {}
+
+func (BaseVisitor) OnROT(sentence *ROT) {}
+
+func (BaseVisitor) OnDBT(sentence *DBT) {}
+
+func (BaseVisitor) OnDPT(sentence *DPT) {}
+
+func (BaseVisitor) OnMTW(sentence *MTW) {}
+
+func (BaseVisitor) OnVHW(sentence *VHW) {}
+
+func (BaseVisitor) OnVLW(sentence *VLW) {}
+
+func (BaseVisitor) OnMWV(sentence *MWV) {}
+
+func (BaseVisitor) OnMWD(sentence *MWD) {}
+
+func (BaseVisitor) OnRMB(sentence *RMB) {}
+
+func (BaseVisitor) OnAPB(sentence *APB) {}
+
+func (BaseVisitor) OnXTE(sentence *XTE) {}
+
+func (BaseVisitor) OnBOD(sentence *BOD) {}
+
+func (BaseVisitor) OnBWC(sentence *BWC) {}
+
+func (BaseVisitor) OnWPL(sentence *WPL) {}
+
+func (BaseVisitor) OnRTE(sentence *RTE) {}
+
+func (BaseVisitor) OnVDM(sentence *VDM) {}
+
+func (BaseVisitor) OnSentence(sentence Sentence) {}
+
+// Visit parses each sentence read from reader and calls the visitor.
+//
+// Each sentence is reported to OnAfterParse with the parse error, if any.
+// Invalid sentences are reported as a *ChecksumError, sentences that have no
+// parser as an *UnknownSentenceError, and sentences that have an invalid
+// field as a *FieldError.
+//
+// The sentences are parsed regardless of their talker ID, e.g. $GNGGA is
+// parsed as a GPGGA with the GN Talker.
+//
+// The "message N of M" GPGSV sentences are joined together and OnGPGSV is
+// only called once all the messages of a sky view were read. Likewise, the
+// RTE sentences are joined together and OnRTE is only called once all the
+// messages of a route were read, and the VDM and VDO fragments are joined
+// together and OnVDM is only called once all the fragments of an AIS message
+// were read.
+//
+// Returns nil when the reader reaches EOF or is closed, otherwise, returns
+// the reader error.
+func Visit(reader io.Reader, visitor Visitor, options ...ParseOption) error {
+	return VisitContext(context.Background(), reader, visitor, options...)
+}
+
+// VisitContext is like Visit but also stops, and returns nil, when ctx is
+// done.
+//
+// To stop a read that is blocked waiting for data, the reader must support
+// read deadlines (e.g. *os.File or net.Conn). Its read deadline is set to
+// interrupt the read, and cleared before returning, so the reader can still
+// be used, but the data that was read and not yet parsed is discarded. Other
+// readers are not interrupted, so VisitContext only returns after their read
+// returns.
+func VisitContext(ctx context.Context, reader io.Reader, visitor Visitor, options ...ParseOption) error {
+	return readSentences(ctx, reader, visitorHandler{visitor}, options)
 }
 
-func Visit(reader io.Reader, visitor Visitor) error {
+// receives the sentences read by readSentences.
+type sentenceHandler interface {
+	OnBeforeParse(sentenceType, sentence string) bool
+	OnAfterParse(sentenceType, sentence string, err error)
+	// OnSentence is called with all the parsed sentences, after joining the
+	// multi-sentence messages.
+	OnSentence(sentence Sentence)
+}
+
+// reads and parses the sentences like VisitContext, but delivers all of them
+// to the handler OnSentence.
+func readSentences(ctx context.Context, reader io.Reader, handler sentenceHandler, options []ParseOption) error {
+	interrupted := make(chan struct{})
+
+	stop := context.AfterFunc(ctx, func() {
+		defer close(interrupted)
+		interruptReader(reader)
+	})
+
+	defer func() {
+		// when the read was interrupted, clear the read deadline, so the reader
+		// can still be used.
+		if !stop() {
+			<-interrupted
+			restoreReader(reader)
+		}
+	}()
+
 	scanner := bufio.NewScanner(reader)
 
+	parser := &streamParser{options: newParseOptions(options)}
+
 	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return nil
+		}
+
 		sentence := scanner.Text()
 
-		if !isValidSentence(sentence) {
+		if len(sentence) == 0 {
 			continue
 		}
 
-		endOfTypeIndex := strings.IndexByte(sentence, ',')
-
-		sentenceType := sentence[1:endOfTypeIndex]
+		sentenceType := sentenceTypeOf(sentence)
 
-		if !visitor.OnBeforeParse(sentenceType, sentence) {
+		if !handler.OnBeforeParse(sentenceType, sentence) {
 			continue
 		}
 
-		var err error
+		s, err := parser.parse(sentence)
 
-		switch sentenceType {
-		case "GPGGA":
-			gpgga, err := parseGPGGA(sentence)
+		if s != nil {
+			handler.OnSentence(s)
+		}
+
+		handler.OnAfterParse(sentenceType, sentence, err)
+	}
+
+	err := scanner.Err()
 
-			if err == nil {
-				visitor.OnGPGGA(gpgga)
-			}
+	if ctx.Err() != nil || isClosedError(err) {
+		return nil
+	}
 
-		case "GPRMC":
-			gprmc, err := parseGPRMC(sentence)
+	return err
+}
 
-			if err == nil {
-				visitor.OnGPRMC(gprmc)
-			}
+// delivers the sentences to the Visitor method of their type.
+type visitorHandler struct {
+	Visitor
+}
 
-		case "GPGSA":
-			gpgsa, err := parseGPGSA(sentence)
+func (h visitorHandler) OnSentence(s Sentence) {
+	switch s := s.(type) {
+	case *GPGGA:
+		h.Visitor.OnGPGGA(s)
 
-			if err == nil {
-				visitor.OnGPGSA(gpgsa)
-			}
+	case *GPRMC:
+		h.Visitor.OnGPRMC(s)
 
-		// TODO GPVTG
-		// TODO GPGSV
+	case *GPGSA:
+		h.Visitor.OnGPGSA(s)
 
-		default:
-			err = nil // TODO use a UnknownSentenceError
-		}
+	case *GPGSV:
+		h.Visitor.OnGPGSV(s)
 
-		visitor.OnAfterParse(sentenceType, sentence, err)
+	case *GPVTG:
+		h.Visitor.OnGPVTG(s)
+
+	case *GPZDA:
+		h.Visitor.OnGPZDA(s)
+
+	case *GPGLL:
+		h.Visitor.OnGPGLL(s)
+
+	case *GNGNS:
+		h.Visitor.OnGNGNS(s)
+
+	case *GPGST:
+		h.Visitor.OnGPGST(s)
+
+	case *HDT:
+		h.Visitor.OnHDT(s)
+
+	case *HDM:
+		h.Visitor.OnHDM(s)
+
+	case *HDG:
+		h.Visitor.OnHDG(s)
+
+	case *THS:
+		h.Visitor.OnTHS(s)
+
+	case *ROT:
+		h.Visitor.OnROT(s)
+
+	case *DBT:
+		h.Visitor.OnDBT(s)
+
+	case *DPT:
+		h.Visitor.OnDPT(s)
+
+	case *MTW:
+		h.Visitor.OnMTW(s)
+
+	case *VHW:
+		h.Visitor.OnVHW(s)
+
+	case *VLW:
+		h.Visitor.OnVLW(s)
+
+	case *MWV:
+		h.Visitor.OnMWV(s)
+
+	case *MWD:
+		h.Visitor.OnMWD(s)
+
+	case *RMB:
+		h.Visitor.OnRMB(s)
+
+	case *APB:
+		h.Visitor.OnAPB(s)
+
+	case *XTE:
+		h.Visitor.OnXTE(s)
+
+	case *BOD:
+		h.Visitor.OnBOD(s)
+
+	case *BWC:
+		h.Visitor.OnBWC(s)
+
+	case *WPL:
+		h.Visitor.OnWPL(s)
+
+	case *RTE:
+		h.Visitor.OnRTE(s)
+
+	case *VDM:
+		h.Visitor.OnVDM(s)
+
+	default:
+		h.Visitor.OnSentence(s)
 	}
+}
+
+type readDeadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+// interrupts a blocked read.
+func interruptReader(reader io.Reader) {
+	if r, ok := reader.(readDeadliner); ok {
+		r.SetReadDeadline(time.Now())
+	}
+}
 
-	// TODO handle graceful shutdown of the scanner source. that is, when we close the source, return nil
+// clears the read deadline set by interruptReader.
+func restoreReader(reader io.Reader) {
+	if r, ok := reader.(readDeadliner); ok {
+		r.SetReadDeadline(time.Time{})
+	}
+}
 
-	return scanner.Err()
+// returns whether the error was caused by reading from a closed source.
+func isClosedError(err error) bool {
+	return errors.Is(err, os.ErrClosed) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, io.ErrClosedPipe)
 }
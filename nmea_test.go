@@ -3,24 +3,35 @@
 package nmea
 
 import (
+	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"os"
 	"reflect"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 )
 
 type visitor struct {
+	BaseVisitor
 	result interface{}
+	err    error
 }
 
 func (v *visitor) OnBeforeParse(sentenceType, sentence string) bool {
 	v.result = nil
+	v.err = nil
 	return true
 }
 
-func (v *visitor) OnAfterParse(sentenceType, sentence string, err error) {}
+func (v *visitor) OnAfterParse(sentenceType, sentence string, err error) {
+	v.err = err
+}
 
 func (v *visitor) OnGPGGA(gpgga *GPGGA) {
 	v.result = gpgga
@@ -34,6 +45,114 @@ func (v *visitor) OnGPGSA(gpgsa *GPGSA) {
 	v.result = gpgsa
 }
 
+func (v *visitor) OnGPGSV(gpgsv *GPGSV) {
+	v.result = gpgsv
+}
+
+func (v *visitor) OnGPVTG(gpvtg *GPVTG) {
+	v.result = gpvtg
+}
+
+func (v *visitor) OnGPZDA(gpzda *GPZDA) {
+	v.result = gpzda
+}
+
+func (v *visitor) OnGPGLL(gpgll *GPGLL) {
+	v.result = gpgll
+}
+
+func (v *visitor) OnGNGNS(gngns *GNGNS) {
+	v.result = gngns
+}
+
+func (v *visitor) OnGPGST(gpgst *GPGST) {
+	v.result = gpgst
+}
+
+func (v *visitor) OnHDT(hdt *HDT) {
+	v.result = hdt
+}
+
+func (v *visitor) OnHDM(hdm *HDM) {
+	v.result = hdm
+}
+
+func (v *visitor) OnHDG(hdg *HDG) {
+	v.result = hdg
+}
+
+func (v *visitor) OnTHS(ths *THS) {
+	v.result = ths
+}
+
+func (v *visitor) OnROT(rot *ROT) {
+	v.result = rot
+}
+
+func (v *visitor) OnDBT(dbt *DBT) {
+	v.result = dbt
+}
+
+func (v *visitor) OnDPT(dpt *DPT) {
+	v.result = dpt
+}
+
+func (v *visitor) OnMTW(mtw *MTW) {
+	v.result = mtw
+}
+
+func (v *visitor) OnVHW(vhw *VHW) {
+	v.result = vhw
+}
+
+func (v *visitor) OnVLW(vlw *VLW) {
+	v.result = vlw
+}
+
+func (v *visitor) OnMWV(mwv *MWV) {
+	v.result = mwv
+}
+
+func (v *visitor) OnMWD(mwd *MWD) {
+	v.result = mwd
+}
+
+func (v *visitor) OnRMB(rmb *RMB) {
+	v.result = rmb
+}
+
+func (v *visitor) OnAPB(apb *APB) {
+	v.result = apb
+}
+
+func (v *visitor) OnXTE(xte *XTE) {
+	v.result = xte
+}
+
+func (v *visitor) OnBOD(bod *BOD) {
+	v.result = bod
+}
+
+func (v *visitor) OnBWC(bwc *BWC) {
+	v.result = bwc
+}
+
+func (v *visitor) OnWPL(wpl *WPL) {
+	v.result = wpl
+}
+
+func (v *visitor) OnRTE(rte *RTE) {
+	v.result = rte
+}
+
+func (v *visitor) OnVDM(vdm *VDM) {
+	v.result = vdm
+}
+
+func (v *visitor) OnSentence(sentence Sentence) {
+	v.result = sentence
+}
+
 func (v *visitor) visit(sentence string) (interface{}, error) {
 	err := Visit(strings.NewReader(sentence), v)
 	return v.result, err
@@ -54,10 +173,22 @@ func duration(text string) (result time.Duration) {
 	return
 }
 
+// returns the decimal degrees of a coordinate like parseLatitude and
+// parseLongitude compute them.
+func coordinate(degrees, minutes float64) float64 {
+	return degrees + minutes/60
+}
+
+// sets the raw sentence of the expected parse result.
+func withRaw(expected interface{}, sentence string) interface{} {
+	reflect.ValueOf(expected).Elem().FieldByName("BaseSentence").FieldByName("Text").SetString(sentence)
+	return expected
+}
+
 func checksum(sentence string) string {
 	l := len(sentence)
 
-	if l < 1 || sentence[0] != '$' {
+	if l < 1 || (sentence[0] != '$' && sentence[0] != '!') {
 		return "__"
 	}
 
@@ -84,37 +215,40 @@ var validSentences = []validSentence{
 	validSentence{
 		"$GPGGA,064951.123,,,,,0,0,,,M,,M,,*47",
 		&GPGGA{
+			BaseSentence:   BaseSentence{Talker: TalkerGPS},
 			Time:           duration("6h49m51s123ms"),
 			UsedSatellites: 0,
-			PositionFix:    0,
-			Latitude:       0,
-			Longitude:      0,
-			HDOP:           0,
-			Altitude:       0}},
+			PositionFix:    0}},
 
 	// after a fix.
 	validSentence{
 		"$GPGGA,064951.000,2307.1256,N,12016.4438,E,1,8,0.95,39.9,M,17.8,M,,*63",
 		&GPGGA{
-			Time:           duration("6h49m51s"),
-			UsedSatellites: 8,
-			PositionFix:    1,
-			Latitude:       23.11876,
-			Longitude:      120.274063333333334,
-			HDOP:           0.95,
-			Altitude:       39.9}},
+			BaseSentence:        BaseSentence{Talker: TalkerGPS},
+			Time:                duration("6h49m51s"),
+			UsedSatellites:      8,
+			PositionFix:         1,
+			Latitude:            Some(23.11876),
+			Longitude:           Some(120.274063333333334),
+			CoordinatePrecision: 4,
+			HDOP:                Some[float32](0.95),
+			Altitude:            Some[float32](39.9),
+			GeoidSeparation:     Some[float32](17.8)}},
 
 	// negative latitude and longitude.
 	validSentence{
 		"$GPGGA,064951.000,2307.1256,S,12016.4438,W,1,8,0.95,39.9,M,17.8,M,,*",
 		&GPGGA{
-			Time:           duration("6h49m51s"),
-			UsedSatellites: 8,
-			PositionFix:    1,
-			Latitude:       -23.11876,
-			Longitude:      -120.274063333333334,
-			HDOP:           0.95,
-			Altitude:       39.9}},
+			BaseSentence:        BaseSentence{Talker: TalkerGPS},
+			Time:                duration("6h49m51s"),
+			UsedSatellites:      8,
+			PositionFix:         1,
+			Latitude:            Some(-23.11876),
+			Longitude:           Some(-120.274063333333334),
+			CoordinatePrecision: 4,
+			HDOP:                Some[float32](0.95),
+			Altitude:            Some[float32](39.9),
+			GeoidSeparation:     Some[float32](17.8)}},
 
 	//
 	// GPRMC
@@ -123,37 +257,153 @@ var validSentences = []validSentence{
 	validSentence{
 		"$GPRMC,064951.000,V,,,,,0.00,0.00,260406,,,N*",
 		&GPRMC{
-			Time:      time.Date(2006, 4, 26, 6, 49, 51, 0, time.UTC),
-			Status:    'V',
-			Latitude:  0,
-			Longitude: 0,
-			Mode:      'N',
-			Speed:     0,
-			Heading:   0}},
+			BaseSentence: BaseSentence{Talker: TalkerGPS},
+			Time:         time.Date(2006, 4, 26, 6, 49, 51, 0, time.UTC),
+			Status:       'V',
+			Mode:         'N',
+			Speed:        Some[float32](0),
+			Heading:      Some[float32](0)}},
 
 	// after a fix.
 	validSentence{
 		"$GPRMC,064951.000,A,2307.1256,N,12016.4438,E,0.03,165.48,260406,,,A*",
 		&GPRMC{
-			Time:      time.Date(2006, 4, 26, 6, 49, 51, 0, time.UTC),
-			Status:    'A',
-			Latitude:  23.11876,
-			Longitude: 120.274063333333334,
-			Mode:      'A',
-			Speed:     0.03,
-			Heading:   165.48}},
+			BaseSentence:        BaseSentence{Talker: TalkerGPS},
+			Time:                time.Date(2006, 4, 26, 6, 49, 51, 0, time.UTC),
+			Status:              'A',
+			Latitude:            Some(23.11876),
+			Longitude:           Some(120.274063333333334),
+			CoordinatePrecision: 4,
+			Mode:                'A',
+			Speed:               Some[float32](0.03),
+			Heading:             Some[float32](165.48)}},
 
 	// negative latitude and longitude.
 	validSentence{
 		"$GPRMC,064951.000,A,2307.1256,S,12016.4438,W,0.03,165.48,260406,,,A*",
 		&GPRMC{
-			Time:      time.Date(2006, 4, 26, 6, 49, 51, 0, time.UTC),
-			Status:    'A',
-			Latitude:  -23.11876,
-			Longitude: -120.274063333333334,
-			Mode:      'A',
-			Speed:     0.03,
-			Heading:   165.48}},
+			BaseSentence:        BaseSentence{Talker: TalkerGPS},
+			Time:                time.Date(2006, 4, 26, 6, 49, 51, 0, time.UTC),
+			Status:              'A',
+			Latitude:            Some(-23.11876),
+			Longitude:           Some(-120.274063333333334),
+			CoordinatePrecision: 4,
+			Mode:                'A',
+			Speed:               Some[float32](0.03),
+			Heading:             Some[float32](165.48)}},
+
+	// magnetic variation and NMEA 4.1 navigational status.
+	validSentence{
+		"$GNRMC,064951.000,A,2307.1256,N,12016.4438,E,0.03,165.48,260406,3.05,W,R,S*",
+		&GPRMC{
+			BaseSentence:        BaseSentence{Talker: TalkerGNSS},
+			Time:                time.Date(2006, 4, 26, 6, 49, 51, 0, time.UTC),
+			Status:              'A',
+			Latitude:            Some(23.11876),
+			Longitude:           Some(120.274063333333334),
+			CoordinatePrecision: 4,
+			Mode:                'R',
+			Speed:               Some[float32](0.03),
+			Heading:             Some[float32](165.48),
+			MagneticVariation:   Some[float32](-3.05),
+			NavigationalStatus:  'S'}},
+	validSentence{
+		"$GPRMC,064951.000,A,2307.1256,N,12016.4438,E,0.03,165.48,260406,1.5,E,F*",
+		&GPRMC{
+			BaseSentence:        BaseSentence{Talker: TalkerGPS},
+			Time:                time.Date(2006, 4, 26, 6, 49, 51, 0, time.UTC),
+			Status:              'A',
+			Latitude:            Some(23.11876),
+			Longitude:           Some(120.274063333333334),
+			CoordinatePrecision: 4,
+			Mode:                'F',
+			Speed:               Some[float32](0.03),
+			Heading:             Some[float32](165.48),
+			MagneticVariation:   Some[float32](1.5)}},
+
+	// high precision coordinates.
+	validSentence{
+		"$GPRMC,064951.000,A,2307.1256789,N,12016.4438123,E,0.03,165.48,260406,,,A*",
+		&GPRMC{
+			BaseSentence:        BaseSentence{Talker: TalkerGPS},
+			Time:                time.Date(2006, 4, 26, 6, 49, 51, 0, time.UTC),
+			Status:              'A',
+			Latitude:            Some(coordinate(23, 7.1256789)),
+			Longitude:           Some(coordinate(120, 16.4438123)),
+			CoordinatePrecision: 7,
+			Mode:                'A',
+			Speed:               Some[float32](0.03),
+			Heading:             Some[float32](165.48)}},
+
+	// low precision coordinates.
+	validSentence{
+		"$GPRMC,064951.000,A,2307.12,N,12016.44,E,0.03,165.48,260406,,,A*",
+		&GPRMC{
+			BaseSentence:        BaseSentence{Talker: TalkerGPS},
+			Time:                time.Date(2006, 4, 26, 6, 49, 51, 0, time.UTC),
+			Status:              'A',
+			Latitude:            Some(coordinate(23, 7.12)),
+			Longitude:           Some(coordinate(120, 16.44)),
+			CoordinatePrecision: 2,
+			Mode:                'A',
+			Speed:               Some[float32](0.03),
+			Heading:             Some[float32](165.48)}},
+
+	// RTK fix with differential corrections.
+	validSentence{
+		"$GPGGA,064951.000,2307.1256789,N,12016.4438123,E,4,12,0.6,39.9,M,17.8,M,1.2,0031*",
+		&GPGGA{
+			BaseSentence:        BaseSentence{Talker: TalkerGPS},
+			Time:                duration("6h49m51s"),
+			UsedSatellites:      12,
+			PositionFix:         FixRTK,
+			Latitude:            Some(coordinate(23, 7.1256789)),
+			Longitude:           Some(coordinate(120, 16.4438123)),
+			CoordinatePrecision: 7,
+			HDOP:                Some[float32](0.6),
+			Altitude:            Some[float32](39.9),
+			GeoidSeparation:     Some[float32](17.8),
+			DGPSAge:             Some[float32](1.2),
+			DGPSStationID:       Some[uint16](31)}},
+
+	// multi-constellation talker.
+	validSentence{
+		"$GNGGA,064951.000,2307.1256,N,12016.4438,E,1,12,0.95,39.9,M,17.8,M,,*",
+		&GPGGA{
+			BaseSentence:        BaseSentence{Talker: TalkerGNSS},
+			Time:                duration("6h49m51s"),
+			UsedSatellites:      12,
+			PositionFix:         1,
+			Latitude:            Some(23.11876),
+			Longitude:           Some(120.274063333333334),
+			CoordinatePrecision: 4,
+			HDOP:                Some[float32](0.95),
+			Altitude:            Some[float32](39.9),
+			GeoidSeparation:     Some[float32](17.8)}},
+	validSentence{
+		"$GNRMC,064951.000,A,2307.1256,N,12016.4438,E,0.03,165.48,260406,,,A*",
+		&GPRMC{
+			BaseSentence:        BaseSentence{Talker: TalkerGNSS},
+			Time:                time.Date(2006, 4, 26, 6, 49, 51, 0, time.UTC),
+			Status:              'A',
+			Latitude:            Some(23.11876),
+			Longitude:           Some(120.274063333333334),
+			CoordinatePrecision: 4,
+			Mode:                'A',
+			Speed:               Some[float32](0.03),
+			Heading:             Some[float32](165.48)}},
+
+	// unknown speed and course.
+	validSentence{
+		"$GPRMC,064951.000,A,2307.1256,N,12016.4438,E,,,260406,,,A*",
+		&GPRMC{
+			BaseSentence:        BaseSentence{Talker: TalkerGPS},
+			Time:                time.Date(2006, 4, 26, 6, 49, 51, 0, time.UTC),
+			Status:              'A',
+			Latitude:            Some(23.11876),
+			Longitude:           Some(120.274063333333334),
+			CoordinatePrecision: 4,
+			Mode:                'A'}},
 
 	//
 	// GPGSA
@@ -161,24 +411,778 @@ var validSentences = []validSentence{
 	validSentence{
 		"$GPGSA,A,3,03,04,01,32,22,28,11,,,,,,2.32,0.95,2.11*",
 		&GPGSA{
-			Mode1: 'A',
-			Mode2: '3',
-			SVs:   []byte{3, 4, 1, 32, 22, 28, 11},
-			PDOP:  2.32,
-			HDOP:  0.95,
-			VDOP:  2.11}}}
+			BaseSentence: BaseSentence{Talker: TalkerGPS},
+			Mode1:        'A',
+			Mode2:        '3',
+			SVs:          []byte{3, 4, 1, 32, 22, 28, 11},
+			PDOP:         Some[float32](2.32),
+			HDOP:         Some[float32](0.95),
+			VDOP:         Some[float32](2.11)}},
+
+	// multi-constellation talker.
+	validSentence{
+		"$BDGSA,A,3,201,202,,,,,,,,,,,2.32,0.95,2.11*",
+		&GPGSA{
+			BaseSentence: BaseSentence{Talker: TalkerBeiDouChina},
+			Mode1:        'A',
+			Mode2:        '3',
+			SVs:          []byte{201, 202},
+			PDOP:         Some[float32](2.32),
+			HDOP:         Some[float32](0.95),
+			VDOP:         Some[float32](2.11)}},
+
+	// NMEA 4.10 GNSS system ID.
+	validSentence{
+		"$GNGSA,A,3,80,71,73,79,69,,,,,,,,1.83,1.09,1.47,2*",
+		&GPGSA{
+			BaseSentence: BaseSentence{Talker: TalkerGNSS},
+			Mode1:        'A',
+			Mode2:        '3',
+			SVs:          []byte{80, 71, 73, 79, 69},
+			PDOP:         Some[float32](1.83),
+			HDOP:         Some[float32](1.09),
+			VDOP:         Some[float32](1.47),
+			SystemID:     Some[byte](2)}},
+
+	// before a fix.
+	validSentence{
+		"$GPGSA,A,1,,,,,,,,,,,,,,,*",
+		&GPGSA{
+			BaseSentence: BaseSentence{Talker: TalkerGPS},
+			Mode1:        'A',
+			Mode2:        '1',
+			SVs:          []byte{}}},
+
+	//
+	// GPGSV
+
+	validSentence{
+		"$GPGSV,1,1,02,29,36,029,42,07,,,*",
+		&GPGSV{
+			BaseSentence:     BaseSentence{Talker: TalkerGPS},
+			SatellitesInView: 2,
+			Satellites: []GSVSatellite{
+				{PRN: 29, Elevation: Some[byte](36), Azimuth: Some[uint16](29), SNR: Some[byte](42)},
+				{PRN: 7}}}},
+
+	// NMEA 4.10 signal ID.
+	validSentence{
+		"$GPGSV,1,1,01,29,36,029,42,8*",
+		&GPGSV{
+			BaseSentence:     BaseSentence{Talker: TalkerGPS},
+			SatellitesInView: 1,
+			SignalID:         8,
+			Satellites: []GSVSatellite{
+				{PRN: 29, Elevation: Some[byte](36), Azimuth: Some[uint16](29), SNR: Some[byte](42)}}}},
+
+	// three digits satellite ID.
+	validSentence{
+		"$GPGSV,1,1,01,300,36,029,42*",
+		&GPGSV{
+			BaseSentence:     BaseSentence{Talker: TalkerGPS},
+			SatellitesInView: 1,
+			Satellites: []GSVSatellite{
+				{PRN: 300, Elevation: Some[byte](36), Azimuth: Some[uint16](29), SNR: Some[byte](42)}}}},
+
+	//
+	// GPVTG
+
+	// legacy format.
+	validSentence{
+		"$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*48",
+		&GPVTG{
+			BaseSentence:   BaseSentence{Talker: TalkerGPS},
+			TrueCourse:     Some[float32](54.7),
+			MagneticCourse: Some[float32](34.4),
+			SpeedKnots:     Some[float32](5.5),
+			SpeedKmh:       Some[float32](10.2)}},
+
+	// NMEA 2.3 format.
+	validSentence{
+		"$GPVTG,165.48,T,,M,0.03,N,0.06,K,A*36",
+		&GPVTG{
+			BaseSentence: BaseSentence{Talker: TalkerGPS},
+			TrueCourse:   Some[float32](165.48),
+			SpeedKnots:   Some[float32](0.03),
+			SpeedKmh:     Some[float32](0.06),
+			Mode:         'A'}},
+
+	// before a fix.
+	validSentence{
+		"$GPVTG,,T,,M,,N,,K,N*",
+		&GPVTG{
+			BaseSentence: BaseSentence{Talker: TalkerGPS},
+			Mode:         'N'}},
+
+	//
+	// GPZDA
+
+	validSentence{
+		"$GPZDA,064951.00,26,04,2006,-05,00*",
+		&GPZDA{
+			BaseSentence: BaseSentence{Talker: TalkerGPS},
+			Time:         time.Date(2006, 4, 26, 6, 49, 51, 0, time.UTC),
+			Location:     time.FixedZone("", -5*60*60)}},
+
+	// negative local zone with minutes.
+	validSentence{
+		"$GPZDA,064951.00,26,04,2006,-00,30*",
+		&GPZDA{
+			BaseSentence: BaseSentence{Talker: TalkerGPS},
+			Time:         time.Date(2006, 4, 26, 6, 49, 51, 0, time.UTC),
+			Location:     time.FixedZone("", -30*60)}},
+
+	// without the local zone.
+	validSentence{
+		"$GNZDA,064951.123,26,04,2006,,*",
+		&GPZDA{
+			BaseSentence: BaseSentence{Talker: TalkerGNSS},
+			Time:         time.Date(2006, 4, 26, 6, 49, 51, 123000000, time.UTC)}},
+
+	//
+	// GPGLL
+
+	// legacy format.
+	validSentence{
+		"$GPGLL,2307.1256,N,12016.4438,E,064951.000,A*",
+		&GPGLL{
+			BaseSentence:        BaseSentence{Talker: TalkerGPS},
+			Latitude:            Some(23.11876),
+			Longitude:           Some(120.274063333333334),
+			CoordinatePrecision: 4,
+			Time:                duration("6h49m51s"),
+			Status:              'A'}},
+
+	// NMEA 2.3 format.
+	validSentence{
+		"$GPGLL,2307.1256,S,12016.4438,W,064951.000,A,D*",
+		&GPGLL{
+			BaseSentence:        BaseSentence{Talker: TalkerGPS},
+			Latitude:            Some(-23.11876),
+			Longitude:           Some(-120.274063333333334),
+			CoordinatePrecision: 4,
+			Time:                duration("6h49m51s"),
+			Status:              'A',
+			Mode:                'D'}},
+
+	// before a fix.
+	validSentence{
+		"$GPGLL,,,,,064951.000,V,N*",
+		&GPGLL{
+			BaseSentence: BaseSentence{Talker: TalkerGPS},
+			Time:         duration("6h49m51s"),
+			Status:       'V',
+			Mode:         'N'}},
+
+	//
+	// GNGNS
+
+	validSentence{
+		"$GNGNS,064951.000,2307.1256,N,12016.4438,E,AAN,12,0.95,39.9,17.8,,,V*",
+		&GNGNS{
+			BaseSentence:        BaseSentence{Talker: TalkerGNSS},
+			Time:                duration("6h49m51s"),
+			Latitude:            Some(23.11876),
+			Longitude:           Some(120.274063333333334),
+			CoordinatePrecision: 4,
+			Modes:               GNSModes{TalkerGPS: 'A', TalkerGLONASS: 'A', TalkerGalileo: 'N'},
+			UsedSatellites:      12,
+			HDOP:                Some[float32](0.95),
+			Altitude:            Some[float32](39.9),
+			GeoidSeparation:     Some[float32](17.8),
+			NavigationalStatus:  'V'}},
+
+	// legacy format with DGPS.
+	validSentence{
+		"$GPGNS,064951.000,2307.1256,S,12016.4438,W,D,08,0.95,39.9,17.8,1.2,0031*",
+		&GNGNS{
+			BaseSentence:        BaseSentence{Talker: TalkerGPS},
+			Time:                duration("6h49m51s"),
+			Latitude:            Some(-23.11876),
+			Longitude:           Some(-120.274063333333334),
+			CoordinatePrecision: 4,
+			Modes:               GNSModes{TalkerGPS: 'D'},
+			UsedSatellites:      8,
+			HDOP:                Some[float32](0.95),
+			Altitude:            Some[float32](39.9),
+			GeoidSeparation:     Some[float32](17.8),
+			DGPSAge:             Some[float32](1.2),
+			DGPSStationID:       Some[uint16](31)}},
+
+	// before a fix.
+	validSentence{
+		"$GNGNS,064951.000,,,,,NNNNNN,00,,,,,,V*",
+		&GNGNS{
+			BaseSentence:       BaseSentence{Talker: TalkerGNSS},
+			Time:               duration("6h49m51s"),
+			Modes:              GNSModes{TalkerGPS: 'N', TalkerGLONASS: 'N', TalkerGalileo: 'N', TalkerBeiDou: 'N', TalkerQZSS: 'N', TalkerNavIC: 'N'},
+			NavigationalStatus: 'V'}},
+
+	//
+	// GPGST
+
+	validSentence{
+		"$GPGST,064951.000,2.3,1.7,1.2,35.5,1.5,1.4,3.1*",
+		&GPGST{
+			BaseSentence:         BaseSentence{Talker: TalkerGPS},
+			Time:                 duration("6h49m51s"),
+			RMS:                  Some[float32](2.3),
+			SemiMajorError:       Some[float32](1.7),
+			SemiMinorError:       Some[float32](1.2),
+			SemiMajorOrientation: Some[float32](35.5),
+			LatitudeError:        Some[float32](1.5),
+			LongitudeError:       Some[float32](1.4),
+			AltitudeError:        Some[float32](3.1)}},
+
+	// only the RMS.
+	validSentence{
+		"$GNGST,064951.000,2.3,,,,,,*",
+		&GPGST{
+			BaseSentence: BaseSentence{Talker: TalkerGNSS},
+			Time:         duration("6h49m51s"),
+			RMS:          Some[float32](2.3)}},
+
+	//
+	// Heading
+
+	validSentence{
+		"$HEHDT,274.07,T*",
+		&HDT{
+			BaseSentence: BaseSentence{Talker: TalkerNorthSeekingGyro},
+			Heading:      Some[float32](274.07)}},
+
+	validSentence{
+		"$HCHDM,238.5,M*",
+		&HDM{
+			BaseSentence: BaseSentence{Talker: TalkerMagneticCompass},
+			Heading:      Some[float32](238.5)}},
+
+	validSentence{
+		"$HCHDG,98.3,0.5,E,12.6,W*",
+		&HDG{
+			BaseSentence: BaseSentence{Talker: TalkerMagneticCompass},
+			Heading:      Some[float32](98.3),
+			Deviation:    Some[float32](0.5),
+			Variation:    Some[float32](-12.6)}},
+
+	// without deviation and variation.
+	validSentence{
+		"$HCHDG,98.3,,,,*",
+		&HDG{
+			BaseSentence: BaseSentence{Talker: TalkerMagneticCompass},
+			Heading:      Some[float32](98.3)}},
+
+	validSentence{
+		"$GPTHS,77.52,E*",
+		&THS{
+			BaseSentence: BaseSentence{Talker: TalkerGPS},
+			Heading:      Some[float32](77.52),
+			Mode:         'E'}},
+
+	// not valid.
+	validSentence{
+		"$GPTHS,,V*",
+		&THS{
+			BaseSentence: BaseSentence{Talker: TalkerGPS},
+			Mode:         'V'}},
+
+	validSentence{
+		"$HEROT,-3.4,A*",
+		&ROT{
+			BaseSentence: BaseSentence{Talker: TalkerNorthSeekingGyro},
+			RateOfTurn:   Some[float32](-3.4),
+			Status:       'A'}},
+
+	//
+	// Marine instruments
+
+	validSentence{
+		"$SDDBT,36.1,f,11.0,M,6.0,F*",
+		&DBT{
+			BaseSentence: BaseSentence{Talker: TalkerDepthSounder},
+			DepthFeet:    Some[float32](36.1),
+			DepthMeters:  Some[float32](11),
+			DepthFathoms: Some[float32](6)}},
+
+	// only in meters.
+	validSentence{
+		"$SDDBT,,f,11.0,M,,F*",
+		&DBT{
+			BaseSentence: BaseSentence{Talker: TalkerDepthSounder},
+			DepthMeters:  Some[float32](11)}},
+
+	// without the units of the absent values.
+	validSentence{
+		"$SDDBT,,,11.0,M,,*",
+		&DBT{
+			BaseSentence: BaseSentence{Talker: TalkerDepthSounder},
+			DepthMeters:  Some[float32](11)}},
+
+	validSentence{
+		"$SDDPT,11.0,-0.5,100*",
+		&DPT{
+			BaseSentence:       BaseSentence{Talker: TalkerDepthSounder},
+			DepthMeters:        Some[float32](11),
+			OffsetMeters:       Some[float32](-0.5),
+			MaximumRangeMeters: Some[float32](100)}},
+
+	// legacy format.
+	validSentence{
+		"$IIDPT,11.0,0.3*",
+		&DPT{
+			BaseSentence: BaseSentence{Talker: TalkerIntegratedInstrumentation},
+			DepthMeters:  Some[float32](11),
+			OffsetMeters: Some[float32](0.3)}},
+
+	validSentence{
+		"$YXMTW,17.75,C*",
+		&MTW{
+			BaseSentence:       BaseSentence{Talker: TalkerTransducer},
+			TemperatureCelsius: Some[float32](17.75)}},
+
+	validSentence{
+		"$VWVHW,245.1,T,,M,5.2,N,9.6,K*",
+		&VHW{
+			BaseSentence: BaseSentence{Talker: TalkerVelocitySensor},
+			TrueHeading:  Some[float32](245.1),
+			SpeedKnots:   Some[float32](5.2),
+			SpeedKmh:     Some[float32](9.6)}},
+
+	// only the speed, without the heading references.
+	validSentence{
+		"$IIVHW,,,,,5.2,N,9.6,K*",
+		&VHW{
+			BaseSentence: BaseSentence{Talker: TalkerIntegratedInstrumentation},
+			SpeedKnots:   Some[float32](5.2),
+			SpeedKmh:     Some[float32](9.6)}},
+
+	validSentence{
+		"$VWVLW,7803.2,N,0.0,N,7802.5,N,0.0,N*",
+		&VLW{
+			BaseSentence:          BaseSentence{Talker: TalkerVelocitySensor},
+			TotalWaterDistanceNM:  Some[float32](7803.2),
+			WaterDistanceNM:       Some[float32](0),
+			TotalGroundDistanceNM: Some[float32](7802.5),
+			GroundDistanceNM:      Some[float32](0)}},
+
+	// legacy format.
+	validSentence{
+		"$VWVLW,7803.2,N,12.5,N*",
+		&VLW{
+			BaseSentence:         BaseSentence{Talker: TalkerVelocitySensor},
+			TotalWaterDistanceNM: Some[float32](7803.2),
+			WaterDistanceNM:      Some[float32](12.5)}},
+
+	validSentence{
+		"$WIMWV,214.8,R,0.1,K,A*",
+		&MWV{
+			BaseSentence: BaseSentence{Talker: TalkerWeatherInstrument},
+			WindAngle:    Some[float32](214.8),
+			Reference:    'R',
+			WindSpeed:    Some[float32](0.1),
+			SpeedUnit:    SpeedKmh,
+			Status:       'A'}},
+
+	validSentence{
+		"$WIMWD,10.1,T,10.1,M,12,N,6.2,M*",
+		&MWD{
+			BaseSentence:         BaseSentence{Talker: TalkerWeatherInstrument},
+			TrueDirection:        Some[float32](10.1),
+			MagneticDirection:    Some[float32](10.1),
+			SpeedKnots:           Some[float32](12),
+			SpeedMetersPerSecond: Some[float32](6.2)}},
+
+	// only the speed, without the direction references.
+	validSentence{
+		"$WIMWD,,,,,12,N,6.2,M*",
+		&MWD{
+			BaseSentence:         BaseSentence{Talker: TalkerWeatherInstrument},
+			SpeedKnots:           Some[float32](12),
+			SpeedMetersPerSecond: Some[float32](6.2)}},
+
+	//
+	// Navigation
+
+	validSentence{
+		"$GPRMB,A,0.66,L,003,004,4917.24,N,12309.57,W,001.3,052.5,000.5,V,A*",
+		&RMB{
+			BaseSentence:          BaseSentence{Talker: TalkerGPS},
+			Status:                'A',
+			CrossTrackError:       Some[float32](0.66),
+			SteerDirection:        'L',
+			OriginWaypointID:      "003",
+			DestinationWaypointID: "004",
+			DestinationLatitude:   Some(coordinate(49, 17.24)),
+			DestinationLongitude:  Some(-coordinate(123, 9.57)),
+			CoordinatePrecision:   2,
+			RangeNM:               Some[float32](1.3),
+			Bearing:               Some[float32](52.5),
+			ClosingVelocity:       Some[float32](0.5),
+			Mode:                  'A'}},
+
+	// legacy format without an active waypoint.
+	validSentence{
+		"$GPRMB,V,,,,,,,,,,,,V*",
+		&RMB{
+			BaseSentence: BaseSentence{Talker: TalkerGPS},
+			Status:       'V'}},
+
+	validSentence{
+		"$GPAPB,A,A,0.10,R,N,V,A,011,M,DEST,012,M,013,T,A*",
+		&APB{
+			BaseSentence:                BaseSentence{Talker: TalkerGPS},
+			Status1:                     'A',
+			Status2:                     'A',
+			CrossTrackError:             Some[float32](0.1),
+			SteerDirection:              'R',
+			CrossTrackUnits:             'N',
+			PerpendicularPassed:         true,
+			OriginBearing:               Some[float32](11),
+			OriginBearingReference:      'M',
+			DestinationWaypointID:       "DEST",
+			DestinationBearing:          Some[float32](12),
+			DestinationBearingReference: 'M',
+			HeadingToSteer:              Some[float32](13),
+			HeadingToSteerReference:     'T',
+			Mode:                        'A'}},
+
+	// legacy format.
+	validSentence{
+		"$GPAPB,A,A,0.10,R,N,A,V,011,M,DEST,,,,*",
+		&APB{
+			BaseSentence:           BaseSentence{Talker: TalkerGPS},
+			Status1:                'A',
+			Status2:                'A',
+			CrossTrackError:        Some[float32](0.1),
+			SteerDirection:         'R',
+			CrossTrackUnits:        'N',
+			ArrivalCircleEntered:   true,
+			OriginBearing:          Some[float32](11),
+			OriginBearingReference: 'M',
+			DestinationWaypointID:  "DEST"}},
+
+	validSentence{
+		"$GPXTE,A,A,0.67,L,N,A*",
+		&XTE{
+			BaseSentence:    BaseSentence{Talker: TalkerGPS},
+			Status1:         'A',
+			Status2:         'A',
+			CrossTrackError: Some[float32](0.67),
+			SteerDirection:  'L',
+			CrossTrackUnits: 'N',
+			Mode:            'A'}},
+
+	// legacy format without a cross track error.
+	validSentence{
+		"$GPXTE,V,V,,,N*",
+		&XTE{
+			BaseSentence:    BaseSentence{Talker: TalkerGPS},
+			Status1:         'V',
+			Status2:         'V',
+			CrossTrackUnits: 'N'}},
+
+	validSentence{
+		"$GPBOD,099.3,T,105.6,M,POINTB,POINTA*",
+		&BOD{
+			BaseSentence:          BaseSentence{Talker: TalkerGPS},
+			TrueBearing:           Some[float32](99.3),
+			MagneticBearing:       Some[float32](105.6),
+			DestinationWaypointID: "POINTB",
+			OriginWaypointID:      "POINTA"}},
+
+	// from the present position.
+	validSentence{
+		"$GPBOD,097.0,T,,M,POINTB,*",
+		&BOD{
+			BaseSentence:          BaseSentence{Talker: TalkerGPS},
+			TrueBearing:           Some[float32](97),
+			DestinationWaypointID: "POINTB"}},
+
+	// without bearings.
+	validSentence{
+		"$GPBOD,,,,,DEST,*",
+		&BOD{
+			BaseSentence:          BaseSentence{Talker: TalkerGPS},
+			DestinationWaypointID: "DEST"}},
+
+	validSentence{
+		"$GPBWC,220516,5130.02,N,00046.34,W,213.8,T,218.0,M,0004.6,N,EGLM,A*",
+		&BWC{
+			BaseSentence:        BaseSentence{Talker: TalkerGPS},
+			Time:                duration("22h5m16s"),
+			WaypointLatitude:    Some(coordinate(51, 30.02)),
+			WaypointLongitude:   Some(-coordinate(0, 46.34)),
+			CoordinatePrecision: 2,
+			TrueBearing:         Some[float32](213.8),
+			MagneticBearing:     Some[float32](218),
+			DistanceNM:          Some[float32](4.6),
+			WaypointID:          "EGLM",
+			Mode:                'A'}},
+
+	// legacy format.
+	validSentence{
+		"$GPBWC,081837,,,,,,T,,M,,N,*",
+		&BWC{
+			BaseSentence: BaseSentence{Talker: TalkerGPS},
+			Time:         duration("8h18m37s")}},
+
+	validSentence{
+		"$GPWPL,4917.16,N,12310.64,W,003*",
+		&WPL{
+			BaseSentence:        BaseSentence{Talker: TalkerGPS},
+			Latitude:            Some(coordinate(49, 17.16)),
+			Longitude:           Some(-coordinate(123, 10.64)),
+			CoordinatePrecision: 2,
+			WaypointID:          "003"}},
+
+	// WPL without a position.
+	validSentence{
+		"$GPWPL,,,,,003*",
+		&WPL{
+			BaseSentence: BaseSentence{Talker: TalkerGPS},
+			WaypointID:   "003"}},
+
+	// single message route.
+	validSentence{
+		"$GPRTE,1,1,c,0,PBRCPK,PBRTO,PTELGR*",
+		&RTE{
+			BaseSentence: BaseSentence{Talker: TalkerGPS},
+			Mode:         'c',
+			RouteID:      "0",
+			Waypoints:    []string{"PBRCPK", "PBRTO", "PTELGR"}}},
+
+	//
+	// AIS
+
+	validSentence{
+		"!AIVDM,1,1,,A,15RTgt0PAso;90TKcjM8h6g208CQ,0*4A",
+		&VDM{
+			BaseSentence: BaseSentence{Talker: TalkerAIS},
+			Channel:      'A',
+			Payload:      "15RTgt0PAso;90TKcjM8h6g208CQ",
+			Message: &AISPositionReport{
+				AISHeader:        AISHeader{MessageType: 1, MMSI: 371798000},
+				RateOfTurn:       -127,
+				SpeedOverGround:  Some[float32](12.3),
+				PositionAccuracy: true,
+				Longitude:        Some(-74037230.0 / 600000),
+				Latitude:         Some(29028980.0 / 600000),
+				CourseOverGround: Some[float32](224),
+				TrueHeading:      Some[uint16](215),
+				Timestamp:        33,
+				RadioStatus:      34017}}},
+
+	// own vessel.
+	validSentence{
+		"!AIVDO,1,1,,,B52K>;h00Fc>jpUlNV@ikwpUoP06,0*",
+		&VDM{
+			BaseSentence: BaseSentence{Talker: TalkerAIS},
+			OwnVessel:    true,
+			Payload:      "B52K>;h00Fc>jpUlNV@ikwpUoP06",
+			Message: &AISClassBPositionReport{
+				AISHeader:        AISHeader{MessageType: 18, MMSI: 338087471},
+				SpeedOverGround:  Some[float32](0.1),
+				Longitude:        Some(-44443279.0 / 600000),
+				Latitude:         Some(24410724.0 / 600000),
+				CourseOverGround: Some[float32](79.6),
+				Timestamp:        49,
+				CSUnit:           true,
+				DSC:              true,
+				Band:             true,
+				Message22:        true,
+				RAIM:             true,
+				RadioStatus:      917510}}},
+
+	validSentence{
+		"!AIVDM,1,1,,B,C5N3SRgPEnJGEBT>NhWAwwo862PaLELTBJ:V00000000S0D:R220,0*0B",
+		&VDM{
+			BaseSentence: BaseSentence{Talker: TalkerAIS},
+			Channel:      'B',
+			Payload:      "C5N3SRgPEnJGEBT>NhWAwwo862PaLELTBJ:V00000000S0D:R220",
+			Message: &AISExtendedClassBPositionReport{
+				AISHeader:        AISHeader{MessageType: 19, MMSI: 367059850},
+				SpeedOverGround:  Some[float32](8.7),
+				Longitude:        Some(-53286235.0 / 600000),
+				Latitude:         Some(17726217.0 / 600000),
+				CourseOverGround: Some[float32](335.9),
+				Timestamp:        46,
+				ShipName:         "CAPT.J.RIMES",
+				ShipType:         70,
+				Dimensions:       AISDimensions{ToBow: 5, ToStern: 21, ToPort: 4, ToStarboard: 4},
+				EPFD:             1}}},
+
+	validSentence{
+		"!AIVDM,1,1,,B,E>kb9O9aS@7PUh10dh19@;0Tah2cWrfP:l?M`00003vP100,0*01",
+		&VDM{
+			BaseSentence: BaseSentence{Talker: TalkerAIS},
+			Channel:      'B',
+			Payload:      "E>kb9O9aS@7PUh10dh19@;0Tah2cWrfP:l?M`00003vP100",
+			Message: &AISAidToNavigationReport{
+				AISHeader:  AISHeader{MessageType: 21, MMSI: 993692028},
+				AidType:    19,
+				Name:       "SF OAK BAY BR VAIS E",
+				Longitude:  Some(-73421920.0 / 600000),
+				Latitude:   Some(22683373.0 / 600000),
+				EPFD:       7,
+				Timestamp:  61,
+				VirtualAid: true}}},
+
+	// static data report part A.
+	validSentence{
+		"!AIVDM,1,1,,A,H42O55i18tMET00000000000000,2*6D",
+		&VDM{
+			BaseSentence: BaseSentence{Talker: TalkerAIS},
+			Channel:      'A',
+			Payload:      "H42O55i18tMET00000000000000",
+			FillBits:     2,
+			Message: &AISStaticDataReport{
+				AISHeader: AISHeader{MessageType: 24, MMSI: 271041815},
+				ShipName:  "PROGUY"}}},
+
+	// static data report part B.
+	validSentence{
+		"!AIVDM,1,1,,A,H42O55lti4hhhilD3nink000?050,0*40",
+		&VDM{
+			BaseSentence: BaseSentence{Talker: TalkerAIS},
+			Channel:      'A',
+			Payload:      "H42O55lti4hhhilD3nink000?050",
+			Message: &AISStaticDataReport{
+				AISHeader:    AISHeader{MessageType: 24, MMSI: 271041815},
+				PartNumber:   1,
+				ShipType:     60,
+				VendorID:     "1D0",
+				UnitModel:    12,
+				SerialNumber: 199796,
+				CallSign:     "TC6163",
+				Dimensions:   AISDimensions{ToStern: 15, ToStarboard: 5}}}},
+
+	// unsupported message type.
+	validSentence{
+		"!AIVDM,1,1,,A,403OviQuMGCqWrRO9>E6fE700@GO,0*",
+		&VDM{
+			BaseSentence: BaseSentence{Talker: TalkerAIS},
+			Channel:      'A',
+			Payload:      "403OviQuMGCqWrRO9>E6fE700@GO"}}}
 
 var invalidSentences = []string{
 	// length.
 	"$T*",
 	// checksum.
 	"$GPGGA,064951.000,,,,,0,0,,,M,,M,,*48",
+	// ! is only for the AIS sentences.
+	"!GPGGA,064951.000,,,,,0,0,,,M,,M,,*",
+	"!GPRMC,064951.000,A,2307.1256,N,12016.4438,E,0.03,165.48,260406,,,A*",
 	// units.
 	"$GPGGA,064951.000,,,,,0,0,,,K,,M,,*",
 	// latitude indicator.
 	"$GPGGA,064951.000,2307.1256,X,12016.4438,W,1,8,0.95,39.9,M,17.8,M,,*",
 	// longitude indicator.
-	"$GPGGA,064951.000,2307.1256,N,12016.4438,X,1,8,0.95,39.9,M,17.8,M,,*"}
+	"$GPGGA,064951.000,2307.1256,N,12016.4438,X,1,8,0.95,39.9,M,17.8,M,,*",
+	// coordinates out of range.
+	"$GPGGA,064951.000,9100.0000,N,12016.4438,E,1,8,0.95,39.9,M,17.8,M,,*",
+	"$GPGGA,064951.000,9000.0001,N,12016.4438,E,1,8,0.95,39.9,M,17.8,M,,*",
+	"$GPGGA,064951.000,2360.0000,N,12016.4438,E,1,8,0.95,39.9,M,17.8,M,,*",
+	"$GPGGA,064951.000,2307.1256,N,18100.0000,E,1,8,0.95,39.9,M,17.8,M,,*",
+	"$GPGGA,064951.000,2307.1256,N,12060.0000,E,1,8,0.95,39.9,M,17.8,M,,*",
+	// magnetic variation indicator.
+	"$GPRMC,064951.000,A,2307.1256,N,12016.4438,E,0.03,165.48,260406,3.05,X,A*",
+	// date.
+	"$GPRMC,064951.000,A,2307.1256,N,12016.4438,E,0.03,165.48,310299,,,A*",
+	"$GPRMC,064951.000,A,2307.1256,N,12016.4438,E,0.03,165.48,310406,,,A*",
+	// navigational status.
+	"$GPRMC,064951.000,A,2307.1256,N,12016.4438,E,0.03,165.48,260406,,,A,X*",
+	// mode.
+	"$GPRMC,064951.000,A,2307.1256,N,12016.4438,E,0.03,165.48,260406,,,X*",
+	// position fix.
+	"$GPGGA,064951.000,2307.1256,N,12016.4438,E,9,8,0.95,39.9,M,17.8,M,,*",
+	// geoidal separation units.
+	"$GPGGA,064951.000,2307.1256,N,12016.4438,E,1,8,0.95,39.9,M,17.8,F,,*",
+	// station ID.
+	"$GPGGA,064951.000,2307.1256,N,12016.4438,E,2,8,0.95,39.9,M,17.8,M,1.2,1024*",
+	// latitude format.
+	"$GPGGA,064951.000,230.71256,N,12016.4438,E,1,8,0.95,39.9,M,17.8,M,,*",
+	"$GPGGA,064951.000,2307.1e56,N,12016.4438,E,1,8,0.95,39.9,M,17.8,M,,*",
+	// longitude format.
+	"$GPGGA,064951.000,2307.1256,N,1201.64438,E,1,8,0.95,39.9,M,17.8,M,,*",
+	// message number.
+	"$GPGSV,1,2,01,29,36,029,42*",
+	// elevation.
+	"$GPGSV,1,1,01,29,91,029,42*",
+	// satellite ID.
+	"$GPGSV,1,1,01,65536,36,029,42*",
+	// units.
+	"$GPVTG,165.48,T,,M,0.03,K,0.06,K,A*",
+	// mode.
+	"$GPVTG,165.48,T,,M,0.03,N,0.06,K,X*",
+	// day.
+	"$GPZDA,064951.00,32,04,2006,-05,00*",
+	"$GPZDA,064951.00,31,02,2006,-05,00*",
+	"$GPZDA,064951.00,29,02,2006,-05,00*",
+	// year.
+	"$GPZDA,064951.00,26,04,06,-05,00*",
+	// local zone hours.
+	"$GPZDA,064951.00,26,04,2006,-14,00*",
+	// status.
+	"$GPGLL,2307.1256,N,12016.4438,E,064951.000,X,A*",
+	// mode.
+	"$GPGLL,2307.1256,N,12016.4438,E,064951.000,A,X*",
+	// GNS mode.
+	"$GNGNS,064951.000,2307.1256,N,12016.4438,E,AXN,12,0.95,39.9,17.8,,,V*",
+	"$GNGNS,064951.000,2307.1256,N,12016.4438,E,AAAAAAA,12,0.95,39.9,17.8,,,V*",
+	// navigational status.
+	"$GNGNS,064951.000,2307.1256,N,12016.4438,E,AAN,12,0.95,39.9,17.8,,,X*",
+	// GST error.
+	"$GPGST,064951.000,2.3,1.7,1.2,35.5,1.5,x,3.1*",
+	// heading reference.
+	"$HEHDT,274.07,M*",
+	// heading range.
+	"$HCHDM,360.5,M*",
+	// deviation indicator.
+	"$HCHDG,98.3,0.0,X,12.6,W*",
+	// THS mode.
+	"$GPTHS,77.52,X*",
+	// ROT status.
+	"$HEROT,-3.4,X*",
+	// DBT units.
+	"$SDDBT,36.1,f,11.0,f,6.0,F*",
+	// MTW units.
+	"$YXMTW,17.75,F*",
+	// VHW reference.
+	"$VWVHW,245.1,M,245.1,M,5.2,N,9.6,K*",
+	// MWV reference.
+	"$WIMWV,214.8,X,0.1,K,A*",
+	// MWV units.
+	"$WIMWV,214.8,R,0.1,X,A*",
+	// MWD units.
+	"$WIMWD,10.1,T,10.1,M,12,N,40,N*",
+	// RMB status.
+	"$GPRMB,X,0.66,L,003,004,4917.24,N,12309.57,W,001.3,052.5,000.5,V*",
+	// RMB direction to steer.
+	"$GPRMB,A,0.66,X,003,004,4917.24,N,12309.57,W,001.3,052.5,000.5,V*",
+	// APB units.
+	"$GPAPB,A,A,0.10,R,X,V,V,011,M,DEST,011,M,011,M*",
+	// APB reference.
+	"$GPAPB,A,A,0.10,R,N,V,V,011,X,DEST,011,M,011,M*",
+	// XTE mode.
+	"$GPXTE,A,A,0.67,L,N,X*",
+	// BOD reference.
+	"$GPBOD,099.3,M,105.6,M,POINTB,POINTA*",
+	// BWC units.
+	"$GPBWC,220516,5130.02,N,00046.34,W,213.8,T,218.0,M,0004.6,K,EGLM*",
+	// WPL N/S indicator.
+	"$GPWPL,4917.16,X,12310.64,W,003*",
+	// RTE mode.
+	"$GPRTE,1,1,x,0,PBRCPK*",
+	// RTE message number.
+	"$GPRTE,1,2,c,0,PBRCPK*",
+	// VDM payload.
+	"!AIVDM,1,1,,A,15RTgt0PAso;90TKcjM8h6g208C{,0*",
+	// VDM fill bits.
+	"!AIVDM,1,1,,A,15RTgt0PAso;90TKcjM8h6g208CQ,6*",
+	// VDM channel.
+	"!AIVDM,1,1,,C,15RTgt0PAso;90TKcjM8h6g208CQ,0*",
+	// VDM fragment number.
+	"!AIVDM,1,2,,A,15RTgt0PAso;90TKcjM8h6g208CQ,0*"}
 
 func TestIsValidSentence(t *testing.T) {
 	visitor := &visitor{}
@@ -195,7 +1199,7 @@ func TestIsValidSentence(t *testing.T) {
 			t.Errorf("`%s` should be valid", sentence)
 		}
 
-		expected := v.expected
+		expected := withRaw(v.expected, sentence)
 
 		actual, _ := visitor.visit(sentence)
 
@@ -225,3 +1229,282 @@ func TestIsInvalidSentence(t *testing.T) {
 		}
 	}
 }
+
+func TestParseErrors(t *testing.T) {
+	visitor := &visitor{}
+
+	// checksum.
+	visitor.visit("$GPGGA,064951.000,,,,,0,0,,,M,,M,,*48")
+	var checksumError *ChecksumError
+	if !errors.As(visitor.err, &checksumError) {
+		t.Errorf("expected a ChecksumError but got `%v`", visitor.err)
+	} else if checksumError.Expected != "48" || checksumError.Actual != 0x47 {
+		t.Errorf("unexpected ChecksumError `%v`", checksumError)
+	}
+
+	// malformed.
+	visitor.visit("$T*")
+	if !errors.As(visitor.err, &checksumError) {
+		t.Errorf("expected a ChecksumError but got `%v`", visitor.err)
+	}
+
+	// only the AIS sentences start with !.
+	sentence := "!GPGGA,064951.000,,,,,0,0,,,M,,M,,*"
+	visitor.visit(sentence + checksum(sentence))
+	if !errors.As(visitor.err, &checksumError) {
+		t.Errorf("expected a ChecksumError but got `%v`", visitor.err)
+	}
+
+	// unknown sentence.
+	sentence = "$GPXXX,1,2,3*"
+	visitor.visit(sentence + checksum(sentence))
+	var unknownSentenceError *UnknownSentenceError
+	if !errors.As(visitor.err, &unknownSentenceError) {
+		t.Errorf("expected an UnknownSentenceError but got `%v`", visitor.err)
+	} else if unknownSentenceError.SentenceType != "GPXXX" {
+		t.Errorf("unexpected UnknownSentenceError `%v`", unknownSentenceError)
+	}
+
+	// field.
+	sentence = "$GPGGA,064951.000,2307.1256,X,12016.4438,W,1,8,0.95,39.9,M,17.8,M,,*"
+	visitor.visit(sentence + checksum(sentence))
+	var fieldError *FieldError
+	if !errors.As(visitor.err, &fieldError) {
+		t.Errorf("expected a FieldError but got `%v`", visitor.err)
+	} else {
+		expected := &FieldError{
+			SentenceType: "GPGGA",
+			Index:        1,
+			Name:         "Latitude",
+			Value:        "2307.1256",
+			Err:          fieldError.Err}
+		if !reflect.DeepEqual(expected, fieldError) {
+			t.Errorf("FieldError expected to be `%v` but it's actually `%v`", expected, fieldError)
+		}
+	}
+
+	// coordinates out of range.
+	coordinateTests := []struct {
+		sentence string
+		index    int
+		name     string
+		value    string
+	}{
+		{"$GPGGA,064951.000,9000.5000,N,12016.4438,E,1,8,0.95,39.9,M,17.8,M,,*", 1, "Latitude", "9000.5000"},
+		{"$GPGGA,064951.000,2375.0000,S,12016.4438,E,1,8,0.95,39.9,M,17.8,M,,*", 1, "Latitude", "2375.0000"},
+		{"$GPGGA,064951.000,2307.1256,N,18030.0000,W,1,8,0.95,39.9,M,17.8,M,,*", 3, "Longitude", "18030.0000"},
+		{"$GPGGA,064951.000,2307.1256,N,12060.0000,E,1,8,0.95,39.9,M,17.8,M,,*", 3, "Longitude", "12060.0000"},
+	}
+
+	for _, test := range coordinateTests {
+		visitor.visit(test.sentence + checksum(test.sentence))
+		if !errors.As(visitor.err, &fieldError) {
+			t.Errorf("`%s` expected a FieldError but got `%v`", test.sentence, visitor.err)
+		} else if fieldError.Index != test.index || fieldError.Name != test.name || fieldError.Value != test.value {
+			t.Errorf("`%s` unexpected FieldError `%v`", test.sentence, fieldError)
+		}
+	}
+
+	// the coordinates limits.
+	sentence = "$GPGGA,064951.000,9000.0000,S,18000.0000,W,1,8,0.95,39.9,M,17.8,M,,*"
+	visitor.visit(sentence + checksum(sentence))
+	if visitor.err != nil {
+		t.Errorf("`%s` should not have an error. instead got `%v`", sentence, visitor.err)
+	}
+
+	// valid.
+	sentence = "$GPGSA,A,3,03,04,01,32,22,28,11,,,,,,2.32,0.95,2.11*"
+	visitor.visit(sentence + checksum(sentence))
+	if visitor.err != nil {
+		t.Errorf("`%s` should not have an error. instead got `%v`", sentence, visitor.err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	sentence := "$GNGGA,064951.000,2307.1256,N,12016.4438,E,1,12,0.95,39.9,M,17.8,M,,*46"
+
+	s, err := Parse(sentence)
+	if err != nil {
+		t.Fatalf("`%s` should be valid. instead got `%v`", sentence, err)
+	}
+
+	gpgga, ok := s.(*GPGGA)
+	if !ok {
+		t.Fatalf("`%s` expected to be parsed as *GPGGA but got `%T`", sentence, s)
+	}
+
+	if gpgga.TalkerID() != TalkerGNSS || gpgga.Type() != "GGA" || gpgga.Raw() != sentence || gpgga.Checksum() != 0x46 {
+		t.Errorf(
+			"`%s` expected TalkerID, Type, Raw and Checksum to be GN, GGA, `%s` and 46 but got %s, %s, `%s` and %02X",
+			sentence,
+			sentence,
+			gpgga.TalkerID(),
+			gpgga.Type(),
+			gpgga.Raw(),
+			gpgga.Checksum())
+	}
+
+	// a single GSV message.
+	sentence = "$GPGSV,3,3,09,07,,,26*73"
+
+	s, err = Parse(sentence)
+	if err != nil {
+		t.Fatalf("`%s` should be valid. instead got `%v`", sentence, err)
+	}
+
+	expected := &GPGSVMessage{
+		TotalMessages: 3,
+		MessageNumber: 3,
+		GPGSV: GPGSV{
+			BaseSentence:     BaseSentence{Talker: TalkerGPS, Text: sentence},
+			SatellitesInView: 9,
+			Satellites:       []GSVSatellite{{PRN: 7, SNR: Some[byte](26)}}}}
+
+	if !reflect.DeepEqual(expected, s) {
+		t.Errorf("`%s` result expected to be `%v` but it's actually `%v`", sentence, expected, s)
+	}
+
+	// errors.
+	for _, sentence := range invalidSentences {
+		if strings.HasSuffix(sentence, "*") {
+			sentence += checksum(sentence)
+		}
+
+		s, err := Parse(sentence)
+		if s != nil || err == nil {
+			t.Errorf("`%s` should not be valid. instead got `%v`", sentence, s)
+		}
+	}
+}
+
+// only implements the GPGGA method.
+type ggaVisitor struct {
+	BaseVisitor
+	results []*GPGGA
+}
+
+func (v *ggaVisitor) OnGPGGA(gpgga *GPGGA) {
+	v.results = append(v.results, gpgga)
+}
+
+func TestBaseVisitor(t *testing.T) {
+	sentences := strings.Join([]string{
+		"$GPGGA,064951.000,2307.1256,N,12016.4438,E,1,8,0.95,39.9,M,17.8,M,,*63",
+		"$GPRMC,064951.000,A,2307.1256,N,12016.4438,E,0.03,165.48,260406,,,A*63",
+		"$GPXXX,1,2,3*" + checksum("$GPXXX,1,2,3*"),
+	}, "\r\n")
+
+	visitor := &ggaVisitor{}
+
+	if err := Visit(strings.NewReader(sentences), visitor); err != nil {
+		t.Fatalf("unexpected error `%v`", err)
+	}
+
+	if len(visitor.results) != 1 || visitor.results[0].UsedSatellites != 8 {
+		t.Errorf("expected the GPGGA but got `%v`", visitor.results)
+	}
+}
+
+type notifyVisitor struct {
+	visitor
+	parsed chan struct{}
+}
+
+func (v *notifyVisitor) OnAfterParse(sentenceType, sentence string, err error) {
+	v.parsed <- struct{}{}
+}
+
+func TestVisitContextCancel(t *testing.T) {
+	// net.Pipe supports read deadlines.
+	reader, writer := net.Pipe()
+	defer reader.Close()
+	defer writer.Close()
+
+	// the reader is blocked after the first sentence, cancel it.
+	visitUntilCancelled := func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		visitor := &notifyVisitor{parsed: make(chan struct{}, 1)}
+
+		result := make(chan error)
+
+		go func() {
+			result <- VisitContext(ctx, reader, visitor)
+		}()
+
+		go io.WriteString(writer, "$GPGGA,064951.123,,,,,0,0,,,M,,M,,*47\r\n")
+		<-visitor.parsed
+		cancel()
+
+		select {
+		case err := <-result:
+			if err != nil {
+				t.Errorf("expected nil but got `%v`", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("VisitContext did not stop after the context was cancelled")
+		}
+	}
+
+	visitUntilCancelled()
+
+	// the reader is not closed and its read deadline was cleared, so it can
+	// be visited again.
+	visitUntilCancelled()
+}
+
+func TestVisitClosedSource(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+
+	visitor := &notifyVisitor{parsed: make(chan struct{}, 1)}
+
+	result := make(chan error)
+
+	go func() {
+		result <- Visit(reader, visitor)
+	}()
+
+	io.WriteString(writer, "$GPGGA,064951.123,,,,,0,0,,,M,,M,,*47\r\n")
+	<-visitor.parsed
+	reader.Close()
+
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Errorf("expected nil but got `%v`", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Visit did not stop after the source was closed")
+	}
+}
+
+func TestVisitReaderError(t *testing.T) {
+	expected := errors.New("device disconnected")
+
+	err := Visit(iotest.ErrReader(expected), &visitor{})
+
+	if err != expected {
+		t.Errorf("expected `%v` but got `%v`", expected, err)
+	}
+}
+
+func TestFixQualityString(t *testing.T) {
+	tests := map[FixQuality]string{
+		FixNotAvailable: "Not available",
+		FixRTK:          "RTK",
+		FixFloatRTK:     "Float RTK",
+		FixSimulation:   "Simulation",
+		FixQuality(9):   "FixQuality(9)",
+	}
+
+	for q, expected := range tests {
+		if q.String() != expected {
+			t.Errorf("expected %s but got %s", expected, q.String())
+		}
+	}
+}
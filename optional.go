@@ -0,0 +1,38 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import "fmt"
+
+// Optional holds a field value that can be absent from the sentence, e.g. the
+// HDOP of a GPGGA without a fix. Valid is false when the field is absent, in
+// which case Value is the zero value.
+type Optional[T any] struct {
+	Value T
+	Valid bool
+}
+
+// Some returns a present value.
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{Value: value, Valid: true}
+}
+
+// Get returns the value and whether it's present.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Valid
+}
+
+// Or returns the value when it's present, or the given value otherwise.
+func (o Optional[T]) Or(value T) T {
+	if o.Valid {
+		return o.Value
+	}
+	return value
+}
+
+func (o Optional[T]) String() string {
+	if !o.Valid {
+		return "<absent>"
+	}
+	return fmt.Sprint(o.Value)
+}
@@ -0,0 +1,49 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import "testing"
+
+func TestOptional(t *testing.T) {
+	var absent Optional[float32]
+
+	if value, ok := absent.Get(); ok || value != 0 {
+		t.Errorf("expected an absent value but got `%v` `%v`", value, ok)
+	}
+
+	if absent.Or(99.99) != 99.99 {
+		t.Errorf("expected the default value but got `%v`", absent.Or(99.99))
+	}
+
+	if absent.String() != "<absent>" {
+		t.Errorf("expected `<absent>` but got `%s`", absent.String())
+	}
+
+	present := Some[float32](0)
+
+	if value, ok := present.Get(); !ok || value != 0 {
+		t.Errorf("expected a present zero value but got `%v` `%v`", value, ok)
+	}
+
+	if present.Or(99.99) != 0 {
+		t.Errorf("expected the present value but got `%v`", present.Or(99.99))
+	}
+
+	if present.String() != "0" {
+		t.Errorf("expected `0` but got `%s`", present.String())
+	}
+}
+
+func TestEllipsoidalHeight(t *testing.T) {
+	s := &GPGGA{Altitude: Some[float32](39.5), GeoidSeparation: Some[float32](17.25)}
+
+	if height, ok := s.EllipsoidalHeight().Get(); !ok || height != 56.75 {
+		t.Errorf("expected 56.75 but got `%v` `%v`", height, ok)
+	}
+
+	s.GeoidSeparation = Optional[float32]{}
+
+	if height, ok := s.EllipsoidalHeight().Get(); ok {
+		t.Errorf("expected an absent height but got `%v`", height)
+	}
+}
@@ -0,0 +1,96 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import "sync"
+
+// ParserFunc parses the fields of a sentence that was already validated.
+//
+// base holds the talker ID and the raw sentence, and should be embedded in
+// the returned sentence. fields are the sentence fields without the type and
+// the checksum, e.g. [15.0 M 45.0 M 25.0 M] for $PGRME,15.0,M,45.0,M,25.0,M*1C.
+//
+// dates is the DatePolicy given to Parse (or the DefaultDatePolicy), use its
+// Date method to resolve the dates of the sentence.
+//
+// When a field cannot be parsed, the returned error should be a *FieldError.
+type ParserFunc func(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error)
+
+var (
+	parsersMutex sync.RWMutex
+	parsers      = map[string]ParserFunc{
+		"GGA": parseGPGGA,
+		"RMC": parseGPRMC,
+		"GSA": parseGPGSA,
+		"GSV": parseGPGSV,
+		"VTG": parseGPVTG,
+		"ZDA": parseGPZDA,
+		"GLL": parseGPGLL,
+		"GNS": parseGNGNS,
+		"GST": parseGPGST,
+		"HDT": parseHDT,
+		"HDM": parseHDM,
+		"HDG": parseHDG,
+		"THS": parseTHS,
+		"ROT": parseROT,
+		"DBT": parseDBT,
+		"DPT": parseDPT,
+		"MTW": parseMTW,
+		"VHW": parseVHW,
+		"VLW": parseVLW,
+		"MWV": parseMWV,
+		"MWD": parseMWD,
+		"RMB": parseRMB,
+		"APB": parseAPB,
+		"XTE": parseXTE,
+		"BOD": parseBOD,
+		"BWC": parseBWC,
+		"WPL": parseWPL,
+		"RTE": parseRTE,
+		"VDM": parseVDM,
+		"VDO": parseVDM,
+	}
+)
+
+// RegisterParser registers the parser of a sentence type.
+//
+// The sentenceType can include the talker ID, e.g. PGRME for a proprietary
+// sentence or GPGGA to only match the GPS talker, or it can be just the
+// sentence formatter, e.g. GGA to match all the talkers. When a sentence
+// matches both, the one with the talker ID is used.
+//
+// Registering a sentence type that already has a parser, including a built-in
+// one, replaces it.
+//
+// The sentences parsed by a registered parser go through the same checksum
+// validation and error reporting as the built-in ones, and are delivered to
+// the Visitor OnSentence method.
+func RegisterParser(sentenceType string, fn ParserFunc) {
+	if sentenceType == "" {
+		panic("nmea: RegisterParser sentenceType is empty")
+	}
+
+	if fn == nil {
+		panic("nmea: RegisterParser fn is nil")
+	}
+
+	parsersMutex.Lock()
+	defer parsersMutex.Unlock()
+
+	parsers[sentenceType] = fn
+}
+
+// returns the parser of a sentence type. e.g. GNGGA. first by the full
+// sentence type, then by just the sentence formatter.
+func lookupParser(sentenceType string) ParserFunc {
+	parsersMutex.RLock()
+	defer parsersMutex.RUnlock()
+
+	if fn, ok := parsers[sentenceType]; ok {
+		return fn
+	}
+
+	_, formatter := splitSentenceType(sentenceType)
+
+	return parsers[formatter]
+}
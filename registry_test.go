@@ -0,0 +1,125 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// Garmin Estimated Error Information.
+type pgrme struct {
+	BaseSentence
+	HPE float32 // horizontal position error in meters.
+	VPE float32 // vertical position error in meters.
+	EPE float32 // overall spherical equivalent position error in meters.
+}
+
+func parsePGRME(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("Failed to parse PGRME. invalid number of fields %v", len(fields))
+	}
+
+	result := &pgrme{BaseSentence: base}
+
+	values := []*float32{&result.HPE, &result.VPE, &result.EPE}
+
+	for i, value := range values {
+		if fields[i*2+1] != "M" {
+			return nil, &FieldError{SentenceType: "PGRME", Index: i*2 + 1, Name: "Units", Value: fields[i*2+1], Err: errors.New("unit not supported")}
+		}
+
+		v, err := strconv.ParseFloat(fields[i*2], 32)
+		if err != nil {
+			return nil, &FieldError{SentenceType: "PGRME", Index: i * 2, Name: "Error", Value: fields[i*2], Err: err}
+		}
+		*value = float32(v)
+	}
+
+	return result, nil
+}
+
+// registers the parser until the test finishes, then restores the previous
+// one.
+func registerTestParser(t *testing.T, sentenceType string, fn ParserFunc) {
+	parsersMutex.RLock()
+	previous, ok := parsers[sentenceType]
+	parsersMutex.RUnlock()
+
+	RegisterParser(sentenceType, fn)
+
+	t.Cleanup(func() {
+		parsersMutex.Lock()
+		defer parsersMutex.Unlock()
+
+		if ok {
+			parsers[sentenceType] = previous
+		} else {
+			delete(parsers, sentenceType)
+		}
+	})
+}
+
+func TestRegisterParser(t *testing.T) {
+	registerTestParser(t, "PGRME", parsePGRME)
+
+	sentence := "$PGRME,15.0,M,45.0,M,25.0,M*1C"
+
+	expected := &pgrme{
+		BaseSentence: BaseSentence{Talker: TalkerProprietary, Text: sentence},
+		HPE:          15,
+		VPE:          45,
+		EPE:          25}
+
+	visitor := &visitor{}
+
+	actual, _ := visitor.visit(sentence)
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("`%s` result expected to be `%v` but it's actually `%v`", sentence, expected, actual)
+	}
+
+	if expected.Type() != "GRME" {
+		t.Errorf("`%s` type expected to be GRME but it's actually %s", sentence, expected.Type())
+	}
+
+	// field error.
+	sentence = "$PGRME,15.0,M,45.0,X,25.0,M*09"
+
+	visitor.visit(sentence)
+
+	var fieldError *FieldError
+	if !errors.As(visitor.err, &fieldError) || fieldError.Index != 3 {
+		t.Errorf("`%s` expected a FieldError on field 3 but got `%v`", sentence, visitor.err)
+	}
+
+	// checksum error.
+	sentence = "$PGRME,15.0,M,45.0,M,25.0,M*1D"
+
+	_, err := Parse(sentence)
+
+	var checksumError *ChecksumError
+	if !errors.As(err, &checksumError) {
+		t.Errorf("`%s` expected a ChecksumError but got `%v`", sentence, err)
+	}
+}
+
+func TestProprietarySentenceWithoutFormatter(t *testing.T) {
+	talker, formatter := splitSentenceType("P")
+	if talker != "" || formatter != "P" {
+		t.Errorf("P expected to be split into no talker ID and P but got `%s` and `%s`", talker, formatter)
+	}
+
+	sentence := "$P,15.0,M*"
+	sentence += checksum(sentence)
+
+	_, err := Parse(sentence)
+
+	var unknownSentenceError *UnknownSentenceError
+	if !errors.As(err, &unknownSentenceError) || unknownSentenceError.SentenceType != "P" {
+		t.Errorf("`%s` expected an UnknownSentenceError but got `%v`", sentence, err)
+	}
+}
@@ -0,0 +1,275 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RTE is the complete route, that is, all the waypoints of all the
+// "message N of M" sentences.
+//
+// NB Raw returns all the sentences joined by CRLF.
+type RTE struct {
+	BaseSentence
+	Mode      byte // c=Complete route, all the waypoints; w=Working route, the first waypoint is the origin and the second the destination.
+	RouteID   string
+	Waypoints []string // waypoint IDs.
+}
+
+// RTEMessage is a single "message N of M" RTE sentence. Its Waypoints are
+// only the ones of this message.
+type RTEMessage struct {
+	TotalMessages byte
+	MessageNumber byte
+	RTE
+}
+
+// Routes.
+//
+// Example:
+//
+//	$GPRTE,2,1,c,0,PBRCPK,PBRTO,PTELGR,PPLAND,PYAMBU,PPFAIR,PWARRN,PMORTL,PLISMR*73
+//	$GPRTE,2,2,c,0,PCRESY,GRYRIE,GCORIO,GWERR,GWESTG,7FED*34
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | Number of     | 2          |         |                              |
+// |    | Messages      |            |         |                              |
+// |  1 | Message       | 1          |         |                              |
+// |    | Number        |            |         |                              |
+// |  2 | Mode          | c          |         | c=Complete route             |
+// |    |               |            |         | w=Working route              |
+// |  3 | Route ID      | 0          |         |                              |
+// |  4 | Waypoint ID   | PBRCPK     |         |                              |
+// | .. | ...           |            |         | ...                          |
+// +----+---------------+------------+---------+------------------------------+
+//
+// NB The messages have as many waypoints as fit in a sentence.
+func parseRTE(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	result := &RTEMessage{}
+	result.BaseSentence = base
+
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	//
+	// number of messages.
+	totalMessages, err := strconv.ParseUint(fields[0], 10, 8)
+	if err != nil || totalMessages < 1 {
+		return nil, newFieldError(sentenceType, fields, 0, "Number of Messages", fmt.Errorf("Failed to parse number of messages %s", fields[0]))
+	}
+	result.TotalMessages = byte(totalMessages)
+
+	//
+	// message number.
+	messageNumber, err := strconv.ParseUint(fields[1], 10, 8)
+	if err != nil || messageNumber < 1 || messageNumber > totalMessages {
+		return nil, newFieldError(sentenceType, fields, 1, "Message Number", fmt.Errorf("Failed to parse message number %s", fields[1]))
+	}
+	result.MessageNumber = byte(messageNumber)
+
+	//
+	// mode.
+	if fields[2] != "c" && fields[2] != "w" {
+		return nil, newFieldError(sentenceType, fields, 2, "Mode", fmt.Errorf("Failed to parse mode %s", fields[2]))
+	}
+	result.Mode = fields[2][0]
+
+	//
+	// route ID.
+	result.RouteID = fields[3]
+
+	//
+	// waypoints.
+	waypoints := make([]string, 0, len(fields)-4)
+	for _, waypoint := range fields[4:] {
+		// skip the empty fields that some receivers send to pad the sentence.
+		if len(waypoint) == 0 {
+			continue
+		}
+		waypoints = append(waypoints, waypoint)
+	}
+	result.Waypoints = waypoints
+
+	return result, nil
+}
+
+// RTEAssembler joins the "message N of M" RTE sentences into a single RTE.
+// The zero value is ready to use.
+//
+// The messages can arrive in any order. When a message does not belong to
+// the route that is being assembled (e.g. its number was already seen or the
+// number of messages differs) the incomplete route is discarded and a new one
+// is started.
+//
+// Each talker and route ID pair is assembled independently.
+type RTEAssembler struct {
+	pending map[rteKey]*rtePending
+}
+
+type rteKey struct {
+	talker  TalkerID
+	routeID string
+}
+
+type rtePending struct {
+	totalMessages byte
+	mode          byte
+	messages      []*RTEMessage // indexed by message number - 1.
+	count         int
+}
+
+// Add adds a message. Returns the complete route when all its messages were
+// added, or nil otherwise.
+func (a *RTEAssembler) Add(message *RTEMessage) *RTE {
+	if a.pending == nil {
+		a.pending = make(map[rteKey]*rtePending)
+	}
+
+	key := rteKey{message.Talker, message.RouteID}
+
+	p := a.pending[key]
+
+	if p == nil ||
+		p.totalMessages != message.TotalMessages ||
+		p.mode != message.Mode ||
+		p.messages[message.MessageNumber-1] != nil {
+		p = &rtePending{
+			totalMessages: message.TotalMessages,
+			mode:          message.Mode,
+			messages:      make([]*RTEMessage, message.TotalMessages),
+		}
+		a.pending[key] = p
+	}
+
+	p.messages[message.MessageNumber-1] = message
+	p.count++
+
+	if p.count < int(p.totalMessages) {
+		return nil
+	}
+
+	delete(a.pending, key)
+
+	result := &RTE{
+		Mode:    message.Mode,
+		RouteID: message.RouteID,
+	}
+
+	sentences := make([]string, len(p.messages))
+
+	for i, m := range p.messages {
+		result.Waypoints = append(result.Waypoints, m.Waypoints...)
+		sentences[i] = m.Text
+	}
+
+	result.BaseSentence = BaseSentence{
+		Talker: message.Talker,
+		Text:   strings.Join(sentences, "\r\n"),
+	}
+
+	return result
+}
+
+func (RTE) Type() string {
+	return "RTE"
+}
+
+func (s *RTE) String() string {
+	return marshalString(s)
+}
+
+func (s *RTEMessage) String() string {
+	return marshalString(s)
+}
+
+func (s *RTEMessage) marshalFields(e *Encoder) []string {
+	fields := []string{
+		strconv.Itoa(int(s.TotalMessages)),
+		strconv.Itoa(int(s.MessageNumber)),
+		formatByte(s.Mode),
+		s.RouteID,
+	}
+
+	return append(fields, s.Waypoints...)
+}
+
+// splits the route into its "message N of M" sentences, each with as many
+// waypoints as fit in a sentence.
+func (e *Encoder) marshalRTE(s *RTE) (string, error) {
+	talker := s.TalkerID()
+	if talker == "" {
+		talker = TalkerGPS
+	}
+
+	// the header length depends on the width of the number of messages, so
+	// split again with a wider one until it fits.
+	var messageWaypoints [][]string
+
+	for numberLength := 1; ; numberLength++ {
+		headerLength := len(talker) + len(s.Type()) + 1 + numberLength + 1 + numberLength + 1 + 1 + 1 + len(s.RouteID)
+
+		messageWaypoints = splitWaypoints(s.Waypoints, maxSentenceDataLength-headerLength)
+
+		if len(strconv.Itoa(len(messageWaypoints))) <= numberLength {
+			break
+		}
+	}
+
+	if len(messageWaypoints) > 255 {
+		return "", fmt.Errorf("Failed to marshal %s: route of %d waypoints needs more than 255 messages", s.Type(), len(s.Waypoints))
+	}
+
+	sentences := make([]string, len(messageWaypoints))
+
+	for i, waypoints := range messageWaypoints {
+		message := &RTEMessage{
+			TotalMessages: byte(len(messageWaypoints)),
+			MessageNumber: byte(i + 1),
+			RTE: RTE{
+				BaseSentence: s.BaseSentence,
+				Mode:         s.Mode,
+				RouteID:      s.RouteID,
+				Waypoints:    waypoints,
+			},
+		}
+
+		sentence, err := e.Marshal(message)
+		if err != nil {
+			return "", err
+		}
+
+		sentences[i] = sentence
+	}
+
+	return strings.Join(sentences, "\r\n"), nil
+}
+
+// splits the waypoints into groups whose fields, each preceded by a comma, fit
+// in length characters. a waypoint that does not fit alone has its own group.
+func splitWaypoints(waypoints []string, length int) [][]string {
+	var result [][]string
+
+	start := 0
+	l := 0
+
+	for i, waypoint := range waypoints {
+		if i > start && l+1+len(waypoint) > length {
+			result = append(result, waypoints[start:i])
+			start = i
+			l = 0
+		}
+		l += 1 + len(waypoint)
+	}
+
+	return append(result, waypoints[start:])
+}
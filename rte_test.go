@@ -0,0 +1,150 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type rteVisitor struct {
+	visitor
+	results []*RTE
+}
+
+func (v *rteVisitor) OnRTE(rte *RTE) {
+	v.results = append(v.results, rte)
+}
+
+var (
+	rte1 = "$GPRTE,2,1,c,0,PBRCPK,PBRTO,PTELGR,PPLAND,PYAMBU,PPFAIR,PWARRN,PMORTL,PLISMR*73"
+	rte2 = "$GPRTE,2,2,c,0,PCRESY,GRYRIE,GCORIO,GWERR,GWESTG,7FED*34"
+)
+
+var rteRoute = &RTE{
+	BaseSentence: BaseSentence{Talker: TalkerGPS, Text: rte1 + "\r\n" + rte2},
+	Mode:         'c',
+	RouteID:      "0",
+	Waypoints: []string{
+		"PBRCPK", "PBRTO", "PTELGR", "PPLAND", "PYAMBU", "PPFAIR", "PWARRN", "PMORTL", "PLISMR",
+		"PCRESY", "GRYRIE", "GCORIO", "GWERR", "GWESTG", "7FED"}}
+
+func TestRTEAssembly(t *testing.T) {
+	tests := []struct {
+		name      string
+		sentences []string
+		expected  []*RTE
+	}{
+		{"in order", []string{rte1, rte2}, []*RTE{rteRoute}},
+		{"out of order", []string{rte2, rte1}, []*RTE{rteRoute}},
+		{"incomplete", []string{rte1}, nil},
+		{"repeated", []string{rte1, rte1, rte2, rte2, rte1}, []*RTE{rteRoute, rteRoute}},
+	}
+
+	for _, test := range tests {
+		visitor := &rteVisitor{}
+
+		err := Visit(strings.NewReader(strings.Join(test.sentences, "\r\n")), visitor)
+		if err != nil {
+			t.Errorf("%s: unexpected error `%v`", test.name, err)
+		}
+
+		if !reflect.DeepEqual(test.expected, visitor.results) {
+			t.Errorf("%s: expected `%v` but got `%v`", test.name, test.expected, visitor.results)
+		}
+	}
+}
+
+func TestRTEAssemblyByRouteID(t *testing.T) {
+	sentences := []string{
+		"$GPRTE,2,1,w,1,A,B*",
+		"$GPRTE,1,1,c,2,C*",
+		"$GPRTE,2,2,w,1,D*",
+	}
+
+	for i, sentence := range sentences {
+		sentences[i] = sentence + checksum(sentence)
+	}
+
+	visitor := &rteVisitor{}
+
+	Visit(strings.NewReader(strings.Join(sentences, "\n")), visitor)
+
+	expected := []*RTE{
+		&RTE{
+			BaseSentence: BaseSentence{Talker: TalkerGPS, Text: sentences[1]},
+			Mode:         'c',
+			RouteID:      "2",
+			Waypoints:    []string{"C"}},
+		&RTE{
+			BaseSentence: BaseSentence{Talker: TalkerGPS, Text: sentences[0] + "\r\n" + sentences[2]},
+			Mode:         'w',
+			RouteID:      "1",
+			Waypoints:    []string{"A", "B", "D"}}}
+
+	if !reflect.DeepEqual(expected, visitor.results) {
+		t.Errorf("expected `%v` but got `%v`", expected, visitor.results)
+	}
+}
+
+func TestMarshalRTE(t *testing.T) {
+	actual, err := Marshal(rteRoute)
+	if err != nil {
+		t.Fatalf("unexpected error `%v`", err)
+	}
+
+	expected := rte1 + "\r\n" + rte2
+
+	if actual != expected {
+		t.Errorf("expected `%s` but it's actually `%s`", expected, actual)
+	}
+
+	// a long route is split into as many sentences as needed to keep each
+	// one within 82 characters, including the CRLF.
+	route := &RTE{Mode: 'c', RouteID: "ROUTE"}
+	for i := 0; i < 100; i++ {
+		route.Waypoints = append(route.Waypoints, strings.Repeat(string(rune('A'+i%26)), 1+i%10))
+	}
+
+	actual, err = Marshal(route)
+	if err != nil {
+		t.Fatalf("unexpected error `%v`", err)
+	}
+
+	for _, sentence := range strings.Split(actual, "\r\n") {
+		if len(sentence)+2 > 82 {
+			t.Errorf("`%s` is longer than 82 characters", sentence)
+		}
+	}
+
+	visitor := &rteVisitor{}
+
+	Visit(strings.NewReader(actual), visitor)
+
+	if len(visitor.results) != 1 || !reflect.DeepEqual(route.Waypoints, visitor.results[0].Waypoints) {
+		t.Errorf("`%s` expected to be assembled into `%v` but got `%v`", actual, route, visitor.results)
+	}
+
+	// an empty route.
+	actual, err = Marshal(&RTE{Mode: 'w', RouteID: "1"})
+	if err != nil {
+		t.Fatalf("unexpected error `%v`", err)
+	}
+
+	if expected := "$GPRTE,1,1,w,1*" + checksum("$GPRTE,1,1,w,1*"); actual != expected {
+		t.Errorf("expected `%s` but it's actually `%s`", expected, actual)
+	}
+
+	// more than 255 messages, i.e. more than 255 waypoints that only fit in a
+	// sentence of their own.
+	route = &RTE{Mode: 'c', RouteID: "ROUTE"}
+	for i := 0; i < 256; i++ {
+		route.Waypoints = append(route.Waypoints, strings.Repeat("W", 50))
+	}
+
+	_, err = Marshal(route)
+	if err == nil {
+		t.Error("expected an error for a route that needs more than 255 messages")
+	}
+}
@@ -0,0 +1,145 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import "encoding/hex"
+
+// Sentence is implemented by all the parsed sentences, e.g. *GPGGA.
+type Sentence interface {
+	// TalkerID returns the talker ID. e.g. GN for $GNGGA.
+	TalkerID() TalkerID
+	// Type returns the sentence type without the talker ID. e.g. GGA for $GNGGA.
+	Type() string
+	// Raw returns the sentence as it was received. e.g. $GNGGA,...*4C.
+	Raw() string
+	// Checksum returns the sentence checksum. e.g. 0x4C for $GNGGA,...*4C.
+	Checksum() byte
+}
+
+// BaseSentence implements the Sentence interface and is embedded in all the
+// parsed sentences.
+type BaseSentence struct {
+	Talker TalkerID
+	Text   string // raw sentence. e.g. $GNGGA,...*4C.
+}
+
+func newBaseSentence(sentence string) BaseSentence {
+	talker, _ := splitSentenceType(sentenceTypeOf(sentence))
+
+	return BaseSentence{
+		Talker: talker,
+		Text:   sentence,
+	}
+}
+
+func (s BaseSentence) TalkerID() TalkerID {
+	return s.Talker
+}
+
+func (s BaseSentence) Type() string {
+	_, formatter := splitSentenceType(sentenceTypeOf(s.Text))
+	return formatter
+}
+
+func (s BaseSentence) Raw() string {
+	return s.Text
+}
+
+func (s BaseSentence) Checksum() byte {
+	l := len(s.Text)
+
+	if l < 3 {
+		return 0
+	}
+
+	checksum, err := hex.DecodeString(s.Text[l-2:])
+	if err != nil {
+		return 0
+	}
+
+	return checksum[0]
+}
+
+// Parse parses a single sentence. e.g. $GNGGA,...*4C.
+//
+// The returned error is a *ChecksumError when the sentence is not valid, an
+// *UnknownSentenceError when there is no parser for the sentence type, or a
+// *FieldError when a field cannot be parsed.
+//
+// A GSV sentence is returned as a *GPGSVMessage, use a GPGSVAssembler to join
+// them into the complete sky view. Likewise, an RTE sentence is returned as a
+// *RTEMessage, use an RTEAssembler to join them into the complete route, and
+// an AIS VDM or VDO sentence is returned as a *VDMFragment, use a
+// VDMAssembler to join them into the complete, and decoded, AIS message.
+//
+// Use RegisterParser to parse other sentence types.
+func Parse(sentence string, options ...ParseOption) (Sentence, error) {
+	return parse(sentence, newParseOptions(options))
+}
+
+func parse(sentence string, options parseOptions) (Sentence, error) {
+	err := checkSentence(sentence)
+	if err != nil {
+		return nil, err
+	}
+
+	sentenceType := sentenceTypeOf(sentence)
+
+	parser := lookupParser(sentenceType)
+	if parser == nil {
+		return nil, &UnknownSentenceError{SentenceType: sentenceType}
+	}
+
+	result, err := parser(newBaseSentence(sentence), splitFields(sentence), options.datePolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// parses the sentences of a stream. unlike Parse, it joins the "message N of
+// M" GSV and RTE sentences into a single *GPGSV and *RTE, and the VDM and VDO
+// fragments into a single *VDM.
+type streamParser struct {
+	gpgsvAssembler GPGSVAssembler
+	rteAssembler   RTEAssembler
+	vdmAssembler   VDMAssembler
+	options        parseOptions
+}
+
+// returns a nil sentence and error when the sentence is just a part of an
+// incomplete multi-sentence message.
+func (p *streamParser) parse(sentence string) (Sentence, error) {
+	s, err := parse(sentence, p.options)
+	if err != nil {
+		return nil, err
+	}
+
+	if message, ok := s.(*GPGSVMessage); ok {
+		if gpgsv := p.gpgsvAssembler.Add(message); gpgsv != nil {
+			return gpgsv, nil
+		}
+		return nil, nil
+	}
+
+	if message, ok := s.(*RTEMessage); ok {
+		if rte := p.rteAssembler.Add(message); rte != nil {
+			return rte, nil
+		}
+		return nil, nil
+	}
+
+	if fragment, ok := s.(*VDMFragment); ok {
+		vdm, err := p.vdmAssembler.Add(fragment)
+		if err != nil {
+			return nil, err
+		}
+		if vdm != nil {
+			return vdm, nil
+		}
+		return nil, nil
+	}
+
+	return s, nil
+}
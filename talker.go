@@ -0,0 +1,48 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import "strings"
+
+// TalkerID identifies the kind of device that sent the sentence. e.g. GN in
+// $GNGGA.
+type TalkerID string
+
+const (
+	TalkerGPS         TalkerID = "GP"
+	TalkerGLONASS     TalkerID = "GL"
+	TalkerGalileo     TalkerID = "GA"
+	TalkerBeiDou      TalkerID = "GB"
+	TalkerBeiDouChina TalkerID = "BD" // BeiDou as used by chinese receivers.
+	TalkerQZSS        TalkerID = "GQ"
+	TalkerNavIC       TalkerID = "GI" // Indian Regional Navigation Satellite System.
+	TalkerGNSS        TalkerID = "GN" // Combined multi-constellation solution.
+	TalkerProprietary TalkerID = "P"
+
+	TalkerMagneticCompass     TalkerID = "HC"
+	TalkerNorthSeekingGyro    TalkerID = "HE"
+	TalkerNonNorthSeekingGyro TalkerID = "HN"
+
+	TalkerIntegratedInstrumentation TalkerID = "II"
+	TalkerDepthSounder              TalkerID = "SD"
+	TalkerVelocitySensor            TalkerID = "VW" // e.g. a speed log.
+	TalkerWeatherInstrument         TalkerID = "WI"
+	TalkerTransducer                TalkerID = "YX"
+
+	TalkerAIS TalkerID = "AI" // Mobile AIS station.
+)
+
+// splits the sentence type into the talker ID and the sentence formatter.
+// e.g.: GNGGA is split into GN and GGA, and PMTK001 into P and MTK001. a
+// sentence type that is too short has no talker ID, e.g. P.
+func splitSentenceType(sentenceType string) (TalkerID, string) {
+	if len(sentenceType) > 1 && strings.HasPrefix(sentenceType, string(TalkerProprietary)) {
+		return TalkerProprietary, sentenceType[1:]
+	}
+
+	if len(sentenceType) < 2 {
+		return "", sentenceType
+	}
+
+	return TalkerID(sentenceType[:2]), sentenceType[2:]
+}
@@ -0,0 +1,312 @@
+// Developed by Rui Lopes (ruilopes.com). Released under the LGPLv3 license.
+
+package nmea
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VDM is the complete AIS message, that is, the payload of all the
+// fragments of a VDM (received from other vessels) or VDO (own vessel)
+// sentence.
+//
+// NB Raw returns all the sentences joined by CRLF.
+type VDM struct {
+	BaseSentence
+	OwnVessel bool           // true for a VDO sentence, i.e. a report of the own vessel.
+	MessageID Optional[byte] // sequential message ID. 0 to 9. absent when the message has a single fragment.
+	Channel   byte           // AIS channel. A or B (or 1 or 2). 0 when absent.
+	Payload   string         // six-bit armored payload.
+	FillBits  byte           // number of bits added to complete the last payload character. 0 to 5.
+	Message   AISMessage     // decoded payload. nil when the message type is not supported.
+}
+
+// VDMFragment is a single "fragment N of M" VDM or VDO sentence. Its Payload
+// is only the one of this fragment and its Message is not decoded.
+type VDMFragment struct {
+	TotalFragments byte
+	FragmentNumber byte
+	VDM
+}
+
+// AIS VHF Data-link Message.
+//
+// Example:
+//
+//	!AIVDM,2,1,1,A,55?MbV02;H;s<HtKR20EHE:0@T4@Dn2222222216L961O5Gf0NSQEp6ClRp8,0*1C
+//	!AIVDM,2,2,1,A,88888888880,2*25
+//
+// Fields:
+//
+// +----+---------------+------------+---------+------------------------------+
+// |  # | name          | example    | units   | description                  |
+// +----+---------------+------------+---------+------------------------------+
+// |  0 | Number of     | 2          |         | Range 1 to 9                 |
+// |    | Fragments     |            |         |                              |
+// |  1 | Fragment      | 1          |         | Range 1 to 9                 |
+// |    | Number        |            |         |                              |
+// |  2 | Sequential    | 1          |         | Range 0 to 9, null for a     |
+// |    | Message ID    |            |         | single fragment message      |
+// |  3 | Channel       | A          |         | A or B (or 1 or 2)           |
+// |  4 | Payload       | 55?MbV02.. |         | Six-bit armored              |
+// |  5 | Fill Bits     | 0          |         | Range 0 to 5                 |
+// +----+---------------+------------+---------+------------------------------+
+//
+// NB VDO has the same fields, but it's a report of the own vessel.
+func parseVDM(base BaseSentence, fields []string, dates DatePolicy) (Sentence, error) {
+	sentenceType := sentenceTypeOf(base.Text)
+
+	_, formatter := splitSentenceType(sentenceType)
+
+	result := &VDMFragment{}
+	result.BaseSentence = base
+	result.OwnVessel = formatter == "VDO"
+
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("Failed to parse %s. invalid number of fields %v", sentenceType, len(fields))
+	}
+
+	//
+	// number of fragments.
+	totalFragments, err := strconv.ParseUint(fields[0], 10, 8)
+	if err != nil || totalFragments < 1 || totalFragments > 9 {
+		return nil, newFieldError(sentenceType, fields, 0, "Number of Fragments", fmt.Errorf("Failed to parse number of fragments %s", fields[0]))
+	}
+	result.TotalFragments = byte(totalFragments)
+
+	//
+	// fragment number.
+	fragmentNumber, err := strconv.ParseUint(fields[1], 10, 8)
+	if err != nil || fragmentNumber < 1 || fragmentNumber > totalFragments {
+		return nil, newFieldError(sentenceType, fields, 1, "Fragment Number", fmt.Errorf("Failed to parse fragment number %s", fields[1]))
+	}
+	result.FragmentNumber = byte(fragmentNumber)
+
+	//
+	// sequential message ID. if available.
+	if len(fields[2]) > 0 {
+		messageID, err := strconv.ParseUint(fields[2], 10, 8)
+		if err != nil || messageID > 9 {
+			return nil, newFieldError(sentenceType, fields, 2, "Sequential Message ID", fmt.Errorf("Failed to parse message ID %s", fields[2]))
+		}
+		result.MessageID = Some(byte(messageID))
+	}
+
+	//
+	// channel. if available.
+	switch fields[3] {
+	case "":
+	case "A", "B", "1", "2":
+		result.Channel = fields[3][0]
+	default:
+		return nil, newFieldError(sentenceType, fields, 3, "Channel", fmt.Errorf("Failed to parse channel %s", fields[3]))
+	}
+
+	//
+	// fill bits.
+	fillBits, err := strconv.ParseUint(fields[5], 10, 8)
+	if err != nil || fillBits > 5 {
+		return nil, newFieldError(sentenceType, fields, 5, "Fill Bits", fmt.Errorf("Failed to parse fill bits %s", fields[5]))
+	}
+	result.FillBits = byte(fillBits)
+
+	//
+	// payload.
+	if _, err := newAISPayload(fields[4], result.FillBits); err != nil {
+		return nil, newFieldError(sentenceType, fields, 4, "Payload", err)
+	}
+	result.Payload = fields[4]
+
+	return result, nil
+}
+
+// VDMAssembler joins the "fragment N of M" VDM and VDO sentences into a
+// single VDM and decodes its payload. The zero value is ready to use.
+//
+// The fragments can arrive in any order. When a fragment does not belong to
+// the message that is being assembled (e.g. its number was already seen or
+// the number of fragments differs) the incomplete message is discarded and a
+// new one is started.
+//
+// Each talker, sentence type, sequential message ID and channel is assembled
+// independently.
+type VDMAssembler struct {
+	pending map[vdmKey]*vdmPending
+}
+
+type vdmKey struct {
+	talker    TalkerID
+	ownVessel bool
+	messageID Optional[byte]
+	channel   byte
+}
+
+type vdmPending struct {
+	totalFragments byte
+	fragments      []*VDMFragment // indexed by fragment number - 1.
+	count          int
+}
+
+// Add adds a fragment. Returns the complete message when all its fragments
+// were added, or nil otherwise.
+//
+// The returned error is a *FieldError when the payload of the complete
+// message cannot be decoded.
+func (a *VDMAssembler) Add(fragment *VDMFragment) (*VDM, error) {
+	if a.pending == nil {
+		a.pending = make(map[vdmKey]*vdmPending)
+	}
+
+	key := vdmKey{fragment.Talker, fragment.OwnVessel, fragment.MessageID, fragment.Channel}
+
+	p := a.pending[key]
+
+	if p == nil ||
+		p.totalFragments != fragment.TotalFragments ||
+		p.fragments[fragment.FragmentNumber-1] != nil {
+		p = &vdmPending{
+			totalFragments: fragment.TotalFragments,
+			fragments:      make([]*VDMFragment, fragment.TotalFragments),
+		}
+		a.pending[key] = p
+	}
+
+	p.fragments[fragment.FragmentNumber-1] = fragment
+	p.count++
+
+	if p.count < int(p.totalFragments) {
+		return nil, nil
+	}
+
+	delete(a.pending, key)
+
+	last := p.fragments[len(p.fragments)-1]
+
+	result := &VDM{
+		OwnVessel: fragment.OwnVessel,
+		MessageID: fragment.MessageID,
+		Channel:   fragment.Channel,
+		FillBits:  last.FillBits,
+	}
+
+	payloads := make([]string, len(p.fragments))
+	sentences := make([]string, len(p.fragments))
+
+	for i, f := range p.fragments {
+		payloads[i] = f.Payload
+		sentences[i] = f.Text
+	}
+
+	result.Payload = strings.Join(payloads, "")
+
+	result.BaseSentence = BaseSentence{
+		Talker: fragment.Talker,
+		Text:   strings.Join(sentences, "\r\n"),
+	}
+
+	message, err := decodeAIS(result.Payload, result.FillBits)
+	if err != nil {
+		return nil, &FieldError{
+			SentenceType: sentenceTypeOf(last.Text),
+			Index:        4,
+			Name:         "Payload",
+			Value:        result.Payload,
+			Err:          err,
+		}
+	}
+	result.Message = message
+
+	return result, nil
+}
+
+func (s VDM) Type() string {
+	if s.OwnVessel {
+		return "VDO"
+	}
+	return "VDM"
+}
+
+func (s *VDM) String() string {
+	return marshalString(s)
+}
+
+func (s *VDMFragment) String() string {
+	return marshalString(s)
+}
+
+func (s *VDMFragment) marshalFields(e *Encoder) []string {
+	return []string{
+		strconv.Itoa(int(s.TotalFragments)),
+		strconv.Itoa(int(s.FragmentNumber)),
+		formatOptionalInt(s.MessageID, 1),
+		formatByte(s.Channel),
+		s.Payload,
+		strconv.Itoa(int(s.FillBits)),
+	}
+}
+
+// the maximum number of payload characters of a fragment, i.e. what is left
+// of a sentence after the other fields. e.g. AIVDM,9,9,9,A,...,5.
+const maxVDMPayloadLength = maxSentenceDataLength - len("AIVDM,9,9,9,A,") - len(",5")
+
+// splits the message into its "fragment N of M" sentences, each with as much
+// of the payload as fits in a sentence. the Message is not marshaled, the
+// Payload is.
+//
+// a message with more than one fragment and without a MessageID is assigned
+// the next sequential message ID of the Encoder, so the fragments can be joined
+// again.
+func (e *Encoder) marshalVDM(s *VDM) (string, error) {
+	totalFragments := (len(s.Payload) + maxVDMPayloadLength - 1) / maxVDMPayloadLength
+	if totalFragments == 0 {
+		totalFragments = 1
+	}
+
+	if totalFragments > 9 {
+		return "", fmt.Errorf("Failed to marshal %s: payload of %d characters needs more than 9 fragments", s.Type(), len(s.Payload))
+	}
+
+	messageID := s.MessageID
+	if totalFragments > 1 && !messageID.Valid {
+		messageID = Some(byte(e.lastMessageID.Add(1) % 10))
+	}
+
+	sentences := make([]string, totalFragments)
+
+	for i := range sentences {
+		payload := s.Payload[i*maxVDMPayloadLength:]
+		if len(payload) > maxVDMPayloadLength {
+			payload = payload[:maxVDMPayloadLength]
+		}
+
+		// only the last fragment has fill bits.
+		fillBits := byte(0)
+		if i == totalFragments-1 {
+			fillBits = s.FillBits
+		}
+
+		fragment := &VDMFragment{
+			TotalFragments: byte(totalFragments),
+			FragmentNumber: byte(i + 1),
+			VDM: VDM{
+				BaseSentence: s.BaseSentence,
+				OwnVessel:    s.OwnVessel,
+				MessageID:    messageID,
+				Channel:      s.Channel,
+				Payload:      payload,
+				FillBits:     fillBits,
+			},
+		}
+
+		sentence, err := e.Marshal(fragment)
+		if err != nil {
+			return "", err
+		}
+
+		sentences[i] = sentence
+	}
+
+	return strings.Join(sentences, "\r\n"), nil
+}